@@ -0,0 +1,143 @@
+package connecterrors
+
+import (
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// BadRequestBuilder collects field violations and builds a domain error
+// carrying them in a google.rpc.BadRequest detail. Create one with BadRequest.
+// A BadRequestBuilder is not safe for concurrent use.
+type BadRequestBuilder struct {
+	reg        *Registry
+	code       ErrorCoder
+	data       M
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+// BadRequest starts a field-violation error for the default Registry. The
+// error uses ErrInvalidArgument unless another code is set with Code.
+//
+// Example:
+//
+//	v := cerr.BadRequest()
+//	if !strings.Contains(req.Email, "@") {
+//	    v.Field("email", "must be a valid email address")
+//	}
+//	if req.Age < 18 {
+//	    v.Field("age", "must be >= 18")
+//	}
+//	if err := v.Err(); err != nil {
+//	    return nil, err
+//	}
+func BadRequest() *BadRequestBuilder { return defaultRegistry.BadRequest() }
+
+// BadRequest starts a field-violation error for an error code registered in r.
+// See the package-level BadRequest.
+func (r *Registry) BadRequest() *BadRequestBuilder {
+	return &BadRequestBuilder{reg: r, code: ErrInvalidArgument}
+}
+
+// Field adds a violation for field, a dot-separated path such as
+// "address.zip_code" or "items[2].sku", described by description.
+func (b *BadRequestBuilder) Field(field, description string) *BadRequestBuilder {
+	b.violations = append(b.violations, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+	return b
+}
+
+// Code sets the registered error code and template data of the error.
+// If the template declares {{reason}} and data does not set it, it is
+// filled with a summary of the violations.
+//
+// Example:
+//
+//	cerr.BadRequest().Code(userv1.ErrInvalidSignup, cerr.M{"email": req.Email})
+func (b *BadRequestBuilder) Code(code ErrorCoder, data M) *BadRequestBuilder {
+	b.code = code
+	b.data = data
+	return b
+}
+
+// HasViolations reports whether any violation was added.
+func (b *BadRequestBuilder) HasViolations() bool {
+	return len(b.violations) > 0
+}
+
+// Err returns the domain error for the collected violations, or nil if there
+// are none. The error carries the registered error code metadata, the usual
+// ErrorInfo detail and a google.rpc.BadRequest detail with one FieldViolation
+// per Field call. opts are applied as in New.
+//
+// Err returns error rather than *connect.Error so that a nil result can be
+// returned directly without becoming a non-nil interface.
+func (b *BadRequestBuilder) Err(opts ...Option) error {
+	if len(b.violations) == 0 {
+		return nil
+	}
+
+	data := b.data
+	if e, ok := b.reg.Lookup(ErrorCode(extractCode(b.code))); ok && declaresField(e.MessageTpl, "reason") {
+		if _, set := data["reason"]; !set {
+			data = make(M, len(b.data)+1)
+			for k, v := range b.data {
+				data[k] = v
+			}
+			data["reason"] = b.summary()
+		}
+	}
+
+	detail, err := connect.NewErrorDetail(&errdetails.BadRequest{FieldViolations: b.violations})
+	if err == nil {
+		opts = append([]Option{WithErrorDetails(detail)}, opts...)
+	}
+	return b.reg.New(b.code, data, opts...)
+}
+
+// summary joins the violations as "field: description; ...".
+func (b *BadRequestBuilder) summary() string {
+	parts := make([]string, len(b.violations))
+	for i, v := range b.violations {
+		parts[i] = v.GetField() + ": " + v.GetDescription()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// declaresField reports whether template has a {{field}} placeholder.
+func declaresField(template, field string) bool {
+	for _, f := range TemplateFields(template) {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldViolations returns the field violations of err's google.rpc.BadRequest
+// detail keyed by field. Multiple descriptions for the same field are joined
+// with "; ". Returns nil if err carries no BadRequest detail.
+//
+// Example:
+//
+//	for field, msg := range cerr.FieldViolations(err) {
+//	    form.SetError(field, msg)
+//	}
+func FieldViolations(err error) map[string]string {
+	br, ok := ExtractBadRequest(err)
+	if !ok || len(br.GetFieldViolations()) == 0 {
+		return nil
+	}
+	violations := make(map[string]string, len(br.GetFieldViolations()))
+	for _, v := range br.GetFieldViolations() {
+		if prev, ok := violations[v.GetField()]; ok {
+			violations[v.GetField()] = prev + "; " + v.GetDescription()
+			continue
+		}
+		violations[v.GetField()] = v.GetDescription()
+	}
+	return violations
+}
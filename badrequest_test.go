@@ -0,0 +1,96 @@
+package connecterrors_test
+
+import (
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func TestBadRequest(t *testing.T) {
+	err := connecterrors.BadRequest().
+		Field("email", "must be valid").
+		Field("age", "must be >= 18").
+		Err()
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected *connect.Error, got %T", err)
+	}
+	if connectErr.Code() != connect.CodeInvalidArgument {
+		t.Errorf("Code = %v, want invalid_argument", connectErr.Code())
+	}
+	if code, _ := connecterrors.ExtractErrorCode(connectErr); code != string(connecterrors.ErrInvalidArgument) {
+		t.Errorf("error code = %q, want %q", code, connecterrors.ErrInvalidArgument)
+	}
+	if want := "Invalid argument: email: must be valid; age: must be >= 18"; connectErr.Message() != want {
+		t.Errorf("Message = %q, want %q", connectErr.Message(), want)
+	}
+
+	br, ok := connecterrors.ExtractBadRequest(err)
+	if !ok {
+		t.Fatal("expected BadRequest detail")
+	}
+	if len(br.FieldViolations) != 2 || br.FieldViolations[0].Field != "email" || br.FieldViolations[1].Description != "must be >= 18" {
+		t.Errorf("unexpected violations: %v", br.FieldViolations)
+	}
+	if _, ok := connecterrors.ExtractErrorInfo(err); !ok {
+		t.Error("expected ErrorInfo detail")
+	}
+}
+
+func TestBadRequestNoViolations(t *testing.T) {
+	b := connecterrors.BadRequest()
+	if b.HasViolations() {
+		t.Error("HasViolations = true, want false")
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestBadRequestCode(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{
+		Code:        "ERROR_INVALID_SIGNUP",
+		MessageTpl:  "Signup for '{{email}}' is invalid",
+		ConnectCode: connect.CodeInvalidArgument,
+	})
+
+	err := reg.BadRequest().
+		Code(connecterrors.ErrorCode("ERROR_INVALID_SIGNUP"), connecterrors.M{"email": "a@b"}).
+		Field("password", "too short").
+		Err(connecterrors.WithMeta("x-form", "signup"))
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected *connect.Error, got %T", err)
+	}
+	if connectErr.Message() != "Signup for 'a@b' is invalid" {
+		t.Errorf("Message = %q", connectErr.Message())
+	}
+	if _, ok := connecterrors.ErrorData(err)["reason"]; ok {
+		t.Error("reason must not be added to templates that do not declare it")
+	}
+	if connectErr.Meta().Get("x-form") != "signup" {
+		t.Error("expected options to be applied")
+	}
+}
+
+func TestFieldViolations(t *testing.T) {
+	err := connecterrors.BadRequest().
+		Field("email", "must be valid").
+		Field("email", "is already taken").
+		Field("age", "must be >= 18").
+		Err()
+
+	got := connecterrors.FieldViolations(err)
+	if len(got) != 2 || got["email"] != "must be valid; is already taken" || got["age"] != "must be >= 18" {
+		t.Errorf("FieldViolations = %v", got)
+	}
+	if got := connecterrors.FieldViolations(connecterrors.New(connecterrors.ErrNotFound, nil)); got != nil {
+		t.Errorf("FieldViolations without detail = %v, want nil", got)
+	}
+}
@@ -0,0 +1,302 @@
+// Package catalog exports error catalogs as JSON, YAML and Markdown documents.
+// Catalogs are built from a running Registry or from compiled proto descriptor
+// sets (protoc --descriptor_set_out, buf build -o).
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+	"github.com/balcieren/connect-errors-go/internal/errordef"
+)
+
+// Entry is a single error in an exported catalog.
+type Entry struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	ConnectCode string   `json:"connect_code"`
+	Retryable   bool     `json:"retryable"`
+	Domain      string   `json:"domain,omitempty"`
+	RetryDelay  string   `json:"retry_delay,omitempty"`
+	Params      []string `json:"params,omitempty"`
+	Description string   `json:"description,omitempty"`
+	HelpURL     string   `json:"help_url,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	ReplacedBy  string   `json:"replaced_by,omitempty"`
+
+	// LocalizedMessages maps locales to message templates.
+	LocalizedMessages map[string]string `json:"localized_messages,omitempty"`
+
+	// SensitiveFields lists the params whose values are masked for clients.
+	SensitiveFields []string `json:"sensitive_fields,omitempty"`
+}
+
+// Format is an output format for Write.
+type Format string
+
+// Supported output formats.
+const (
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatMarkdown Format = "markdown"
+)
+
+// FromRegistry builds a catalog from the error definitions in r.
+// Errors without their own Domain report the Registry domain. Templates
+// registered with RegisterMessages are reported as localized messages.
+//
+// Example:
+//
+//	entries := catalog.FromRegistry(cerr.DefaultRegistry())
+//	_ = catalog.WriteMarkdown(os.Stdout, entries)
+func FromRegistry(r *connecterrors.Registry) []Entry {
+	errs := r.Errors()
+	entries := make([]Entry, 0, len(errs))
+	for _, e := range errs {
+		entry := Entry{
+			Code:        string(e.Code),
+			Message:     e.MessageTpl,
+			ConnectCode: e.ConnectCode.String(),
+			Retryable:   e.Retryable,
+			Domain:      e.Domain,
+			Params:      connecterrors.TemplateFields(e.MessageTpl),
+			Description: e.Description,
+			HelpURL:     e.HelpURL,
+			Deprecated:  e.Deprecated,
+			ReplacedBy:  string(e.ReplacedBy),
+
+			LocalizedMessages: r.LocalizedMessages(e.Code),
+			SensitiveFields:   e.SensitiveFields,
+		}
+		if e.Severity != connecterrors.SeverityUnspecified {
+			entry.Severity = e.Severity.String()
+		}
+		if entry.Domain == "" {
+			entry.Domain = r.Domain()
+		}
+		if e.RetryDelay > 0 {
+			entry.RetryDelay = e.RetryDelay.String()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// FromDescriptorSet builds a catalog from the connecterrors.v1 options of
+// every file in set, sorted by code. Identical definitions of a code are
+// merged; it returns an error if a definition is invalid or if two
+// definitions of the same code conflict.
+func FromDescriptorSet(set *descriptorpb.FileDescriptorSet) ([]Entry, error) {
+	var all errordef.Set
+	add := func(defs []errordef.Def, fileDomain, source string) error {
+		for _, d := range defs {
+			if d.Domain == "" {
+				d.Domain = fileDomain
+			}
+			if err := all.Add(d, source); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, f := range set.GetFile() {
+		defs, fileDomain, err := errordef.FileErrors(f.GetOptions())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.GetName(), err)
+		}
+		if err := add(defs, fileDomain, f.GetName()+" (file option)"); err != nil {
+			return nil, err
+		}
+		for _, svc := range f.GetService() {
+			for _, m := range svc.GetMethod() {
+				rpc := fmt.Sprintf("%s.%s.%s", f.GetPackage(), svc.GetName(), m.GetName())
+				defs, err := errordef.MethodErrors(m.GetOptions())
+				if err != nil {
+					return nil, fmt.Errorf("%s: rpc %s: %w", f.GetName(), rpc, err)
+				}
+				if err := add(defs, fileDomain, fmt.Sprintf("%s (rpc %s)", f.GetName(), rpc)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	entries := make([]Entry, 0, len(all.Defs()))
+	for _, d := range all.Defs() {
+		entry := Entry{
+			Code:        d.Code,
+			Message:     d.Message,
+			ConnectCode: connectCodeName(d.ConnectCode),
+			Retryable:   d.Retryable,
+			Domain:      d.Domain,
+			Params:      connecterrors.TemplateFields(d.Message),
+			Description: d.Description,
+			HelpURL:     d.HelpURL,
+			Deprecated:  d.Deprecated,
+			ReplacedBy:  d.ReplacedBy,
+
+			LocalizedMessages: d.LocalizedMessages,
+			SensitiveFields:   d.SensitiveFields,
+		}
+		if s := connecterrors.Severity(d.Severity); s != connecterrors.SeverityUnspecified {
+			entry.Severity = s.String()
+		}
+		if d.RetryDelay > 0 {
+			entry.RetryDelay = d.RetryDelay.String()
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries, nil
+}
+
+// connectCodeName returns the Connect code name for a connecterrors.v1.Code value.
+// Unknown values map to "internal", matching the generated registration code.
+func connectCodeName(code int) string {
+	if code < 1 || code > 16 {
+		return connect.CodeInternal.String()
+	}
+	return connect.Code(code).String()
+}
+
+// Write writes entries to w in the given format.
+func Write(w io.Writer, entries []Entry, format Format) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, entries)
+	case FormatYAML:
+		return WriteYAML(w, entries)
+	case FormatMarkdown:
+		return WriteMarkdown(w, entries)
+	default:
+		return fmt.Errorf("catalog: unknown format %q", format)
+	}
+}
+
+// WriteJSON writes entries as an indented JSON document of the form {"errors": [...]}.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Errors []Entry `json:"errors"`
+	}{entries})
+}
+
+// WriteYAML writes entries as a YAML document with the same shape as WriteJSON.
+// Strings are double-quoted so templates with special characters stay valid.
+func WriteYAML(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+	if len(entries) == 0 {
+		b.WriteString("errors: []\n")
+	} else {
+		b.WriteString("errors:\n")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(&b, "  - code: %s\n", strconv.Quote(e.Code))
+		fmt.Fprintf(&b, "    message: %s\n", strconv.Quote(e.Message))
+		fmt.Fprintf(&b, "    connect_code: %s\n", strconv.Quote(e.ConnectCode))
+		fmt.Fprintf(&b, "    retryable: %t\n", e.Retryable)
+		if e.Domain != "" {
+			fmt.Fprintf(&b, "    domain: %s\n", strconv.Quote(e.Domain))
+		}
+		if e.RetryDelay != "" {
+			fmt.Fprintf(&b, "    retry_delay: %s\n", strconv.Quote(e.RetryDelay))
+		}
+		if len(e.Params) > 0 {
+			b.WriteString("    params:\n")
+			for _, p := range e.Params {
+				fmt.Fprintf(&b, "      - %s\n", strconv.Quote(p))
+			}
+		}
+		if len(e.SensitiveFields) > 0 {
+			b.WriteString("    sensitive_fields:\n")
+			for _, f := range e.SensitiveFields {
+				fmt.Fprintf(&b, "      - %s\n", strconv.Quote(f))
+			}
+		}
+		if e.Description != "" {
+			fmt.Fprintf(&b, "    description: %s\n", strconv.Quote(e.Description))
+		}
+		if e.HelpURL != "" {
+			fmt.Fprintf(&b, "    help_url: %s\n", strconv.Quote(e.HelpURL))
+		}
+		if e.Severity != "" {
+			fmt.Fprintf(&b, "    severity: %s\n", strconv.Quote(e.Severity))
+		}
+		if e.Deprecated {
+			b.WriteString("    deprecated: true\n")
+		}
+		if e.ReplacedBy != "" {
+			fmt.Fprintf(&b, "    replaced_by: %s\n", strconv.Quote(e.ReplacedBy))
+		}
+		if len(e.LocalizedMessages) > 0 {
+			b.WriteString("    localized_messages:\n")
+			locales := make([]string, 0, len(e.LocalizedMessages))
+			for locale := range e.LocalizedMessages {
+				locales = append(locales, locale)
+			}
+			sort.Strings(locales)
+			for _, locale := range locales {
+				fmt.Fprintf(&b, "      %s: %s\n", strconv.Quote(locale), strconv.Quote(e.LocalizedMessages[locale]))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes entries as a Markdown reference table.
+// Descriptions and help links are appended to the message cell, and
+// deprecated codes are marked in the code cell.
+func WriteMarkdown(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+	b.WriteString("| Code | Connect Code | Retryable | Message |\n")
+	b.WriteString("| ---- | ------------ | --------- | ------- |\n")
+	for _, e := range entries {
+		retryable := "No"
+		if e.Retryable {
+			retryable = "Yes"
+			if e.RetryDelay != "" {
+				retryable += " (" + e.RetryDelay + ")"
+			}
+		}
+		code := "`" + e.Code + "`"
+		if e.Deprecated {
+			code += " (deprecated"
+			if e.ReplacedBy != "" {
+				code += ", use `" + e.ReplacedBy + "`"
+			}
+			code += ")"
+		}
+		message := markdownCell(e.Message)
+		if e.Description != "" {
+			message += "<br>" + markdownCell(e.Description)
+		}
+		if e.HelpURL != "" {
+			message += " ([docs](" + e.HelpURL + "))"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", code, e.ConnectCode, retryable, message)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes text for use inside a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,265 @@
+package catalog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	cerr "github.com/balcieren/connect-errors-go"
+	"github.com/balcieren/connect-errors-go/catalog"
+)
+
+// errorDefBytes encodes a connecterrors.v1.ErrorDef in wire format.
+func errorDefBytes(code, message string, connectCode int, retryable bool) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, code)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, message)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(connectCode))
+	if retryable {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return b
+}
+
+func testDescriptorSet() *descriptorpb.FileDescriptorSet {
+	var raw []byte
+	raw = protowire.AppendTag(raw, 50002, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, errorDefBytes("ERROR_RATE_LIMITED", "Too many requests", 8, true))
+	raw = protowire.AppendTag(raw, 50003, protowire.BytesType)
+	raw = protowire.AppendString(raw, "users.acme.com")
+	fileOpts := &descriptorpb.FileOptions{}
+	fileOpts.ProtoReflect().SetUnknown(raw)
+
+	raw = protowire.AppendTag(nil, 50001, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, errorDefBytes("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", 5, false))
+	methodOpts := &descriptorpb.MethodOptions{}
+	methodOpts.ProtoReflect().SetUnknown(raw)
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user.proto"),
+			Package: proto.String("user.v1"),
+			Options: fileOpts,
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("UserService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetUser"), Options: methodOpts},
+					{Name: proto.String("DeleteUser"), Options: methodOpts},
+				},
+			}},
+		}},
+	}
+}
+
+func TestFromDescriptorSet(t *testing.T) {
+	entries, err := catalog.FromDescriptorSet(testDescriptorSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (duplicates removed)", len(entries))
+	}
+	if entries[0].Code != "ERROR_RATE_LIMITED" || entries[1].Code != "ERROR_USER_NOT_FOUND" {
+		t.Errorf("entries not sorted by code: %v", entries)
+	}
+
+	got := entries[1]
+	if got.ConnectCode != "not_found" {
+		t.Errorf("ConnectCode = %q, want not_found", got.ConnectCode)
+	}
+	if got.Domain != "users.acme.com" {
+		t.Errorf("Domain = %q, want file domain users.acme.com", got.Domain)
+	}
+	if len(got.Params) != 1 || got.Params[0] != "id" {
+		t.Errorf("Params = %v, want [id]", got.Params)
+	}
+	if !entries[0].Retryable {
+		t.Error("ERROR_RATE_LIMITED should be retryable")
+	}
+}
+
+func TestFromDescriptorSetConflict(t *testing.T) {
+	set := testDescriptorSet()
+	raw := protowire.AppendTag(nil, 50001, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, errorDefBytes("ERROR_USER_NOT_FOUND", "No user '{{id}}'", 5, false))
+	methodOpts := &descriptorpb.MethodOptions{}
+	methodOpts.ProtoReflect().SetUnknown(raw)
+	set.File = append(set.File, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("admin.proto"),
+		Package: proto.String("admin.v1"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:   proto.String("AdminService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{Name: proto.String("GetUser"), Options: methodOpts}},
+		}},
+	})
+
+	_, err := catalog.FromDescriptorSet(set)
+	if err == nil {
+		t.Fatal("expected error for conflicting definitions")
+	}
+	for _, want := range []string{"ERROR_USER_NOT_FOUND", "user.proto (rpc user.v1.UserService.GetUser)", "admin.proto (rpc admin.v1.AdminService.GetUser)", "message"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestFromRegistry(t *testing.T) {
+	r := cerr.NewRegistry()
+	r.SetDomain("test.acme.com")
+	r.Register(cerr.Error{
+		Code:        "ERROR_QUOTA",
+		MessageTpl:  "Quota for {{resource}} exceeded",
+		ConnectCode: connect.CodeResourceExhausted,
+		Retryable:   true,
+		RetryDelay:  30 * time.Second,
+
+		SensitiveFields: []string{"resource"},
+	})
+
+	var got catalog.Entry
+	for _, e := range catalog.FromRegistry(r) {
+		if e.Code == "ERROR_QUOTA" {
+			got = e
+		}
+	}
+	want := catalog.Entry{
+		Code:        "ERROR_QUOTA",
+		Message:     "Quota for {{resource}} exceeded",
+		ConnectCode: "resource_exhausted",
+		Retryable:   true,
+		Domain:      "test.acme.com",
+		RetryDelay:  "30s",
+		Params:      []string{"resource"},
+	}
+	if got.Code != want.Code || got.ConnectCode != want.ConnectCode || got.Domain != want.Domain ||
+		got.RetryDelay != want.RetryDelay || len(got.Params) != 1 || got.Params[0] != "resource" {
+		t.Errorf("FromRegistry entry = %+v, want %+v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WriteYAML(&buf, []catalog.Entry{got}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "    sensitive_fields:\n      - \"resource\"\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("YAML missing %q\n%s", want, buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	entries, _ := catalog.FromDescriptorSet(testDescriptorSet())
+	if err := catalog.WriteJSON(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Errors []catalog.Entry `json:"errors"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(doc.Errors) != 2 || doc.Errors[1].Code != "ERROR_USER_NOT_FOUND" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	r := cerr.NewRegistry()
+	r.RegisterMessages("de", map[cerr.ErrorCode]string{cerr.ErrNotFound: "Ressource '{{id}}' nicht gefunden"})
+
+	var got catalog.Entry
+	for _, e := range catalog.FromRegistry(r) {
+		if e.Code == string(cerr.ErrNotFound) {
+			got = e
+		}
+	}
+	if got.LocalizedMessages["de"] != "Ressource '{{id}}' nicht gefunden" {
+		t.Fatalf("LocalizedMessages = %v", got.LocalizedMessages)
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WriteYAML(&buf, []catalog.Entry{got}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "    localized_messages:\n      \"de\": \"Ressource '{{id}}' nicht gefunden\"\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("YAML missing %q\n%s", want, buf.String())
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	var buf bytes.Buffer
+	entries, _ := catalog.FromDescriptorSet(testDescriptorSet())
+	if err := catalog.WriteYAML(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"errors:\n",
+		`  - code: "ERROR_USER_NOT_FOUND"`,
+		`    message: "User '{{id}}' not found"`,
+		`    connect_code: "not_found"`,
+		"    retryable: true",
+		`      - "id"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("YAML missing %q\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	_ = catalog.WriteYAML(&buf, nil)
+	if buf.String() != "errors: []\n" {
+		t.Errorf("empty YAML = %q", buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []catalog.Entry{{Code: "ERROR_X", Message: "a | b", ConnectCode: "internal"}}
+	if err := catalog.WriteMarkdown(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| `ERROR_X` | `internal` | No | a \\| b |") {
+		t.Errorf("unexpected markdown:\n%s", buf.String())
+	}
+}
+
+func TestWriteMarkdownDocs(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []catalog.Entry{{
+		Code:        "ERROR_GONE",
+		Message:     "gone",
+		ConnectCode: "not_found",
+		Description: "Returned for deleted users.",
+		HelpURL:     "https://docs.acme.com/errors/gone",
+		Deprecated:  true,
+		ReplacedBy:  "ERROR_NOT_FOUND",
+	}}
+	if err := catalog.WriteMarkdown(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "| `ERROR_GONE` (deprecated, use `ERROR_NOT_FOUND`) | `not_found` | No | gone<br>Returned for deleted users. ([docs](https://docs.acme.com/errors/gone)) |"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected markdown:\n%s", buf.String())
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := catalog.Write(&bytes.Buffer{}, nil, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
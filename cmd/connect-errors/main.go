@@ -0,0 +1,125 @@
+// connect-errors is a command-line tool for working with connect-errors-go
+// error definitions outside of code generation.
+//
+// Usage:
+//
+//	buf build -o image.binpb
+//	connect-errors catalog -format markdown -o ERRORS.md image.binpb
+//
+//	protoc --include_imports --descriptor_set_out=errors.binpb proto/*.proto
+//	connect-errors catalog -format json errors.binpb
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/balcieren/connect-errors-go/catalog"
+)
+
+var version = "0.4.0"
+
+const usage = `Usage: connect-errors <command> [flags]
+
+Commands:
+  catalog   export the error catalog of descriptor sets as json, yaml or markdown
+  version   print version
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "connect-errors: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command in args, writing default output to stdout.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("missing command")
+	}
+
+	switch args[0] {
+	case "catalog":
+		return runCatalog(args[1:], stdout)
+	case "version", "-version", "--version":
+		fmt.Fprintf(stdout, "connect-errors %s\n", version)
+		return nil
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// runCatalog implements the catalog command.
+func runCatalog(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	format := fs.String("format", string(catalog.FormatJSON), "output format: json, yaml or markdown")
+	out := fs.String("o", "", "output file (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: connect-errors catalog [-format json|yaml|markdown] [-o file] <descriptor-set>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no descriptor set given")
+	}
+	switch catalog.Format(*format) {
+	case catalog.FormatJSON, catalog.FormatYAML, catalog.FormatMarkdown:
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, path := range fs.Args() {
+		s, err := readDescriptorSet(path)
+		if err != nil {
+			return err
+		}
+		set.File = append(set.File, s.File...)
+	}
+	entries, err := catalog.FromDescriptorSet(set)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return catalog.Write(stdout, entries, catalog.Format(*format))
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := catalog.Write(f, entries, catalog.Format(*format)); err != nil {
+		f.Close()
+		return err
+	}
+	// Close reports write errors that were deferred by the file system
+	return f.Close()
+}
+
+// readDescriptorSet reads a binary FileDescriptorSet. Buf images are
+// wire-compatible with FileDescriptorSet and can be read the same way.
+func readDescriptorSet(path string) (*descriptorpb.FileDescriptorSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return set, nil
+}
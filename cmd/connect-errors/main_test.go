@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet writes a descriptor set declaring a single file-level error
+// and returns its path.
+func writeDescriptorSet(t *testing.T) string {
+	t.Helper()
+	var def []byte
+	def = protowire.AppendTag(def, 1, protowire.BytesType)
+	def = protowire.AppendString(def, "ERROR_TEST")
+	def = protowire.AppendTag(def, 2, protowire.BytesType)
+	def = protowire.AppendString(def, "Test {{id}}")
+	def = protowire.AppendTag(def, 3, protowire.VarintType)
+	def = protowire.AppendVarint(def, 5)
+
+	raw := protowire.AppendTag(nil, 50002, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, def)
+	opts := &descriptorpb.FileOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{Name: proto.String("test.proto"), Options: opts}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "set.binpb")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCatalog(t *testing.T) {
+	path := writeDescriptorSet(t)
+
+	var out bytes.Buffer
+	if err := run([]string{"catalog", "-format", "markdown", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "| `ERROR_TEST` | `not_found` | No | Test {{id}} |") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRunCatalogOutputFile(t *testing.T) {
+	path := writeDescriptorSet(t)
+	outPath := filepath.Join(t.TempDir(), "errors.json")
+
+	if err := run([]string{"catalog", "-o", outPath, path}, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"code": "ERROR_TEST"`) {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestRunCatalogWriteError(t *testing.T) {
+	// Writes to /dev/full fail with ENOSPC.
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full not available")
+	}
+	if err := run([]string{"catalog", "-o", "/dev/full", writeDescriptorSet(t)}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error when the output file cannot be written")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"unknown"},
+		{"catalog"},
+		{"catalog", "-format", "xml", writeDescriptorSet(t)},
+		{"catalog", filepath.Join(t.TempDir(), "missing.binpb")},
+	}
+	for _, args := range tests {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("run(%q) succeeded, want error", args)
+		}
+	}
+}
@@ -10,12 +10,16 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/compiler/protogen"
-	"google.golang.org/protobuf/encoding/protowire"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/balcieren/connect-errors-go/internal/errordef"
 )
 
 var version = "0.4.0"
@@ -42,58 +46,93 @@ func main() {
 	}
 
 	opts.Run(func(gen *protogen.Plugin) error {
+		// All generated files register into the same default Registry at
+		// runtime, so conflicting definitions are rejected across files too
+		var all errordef.Set
 		for _, f := range gen.Files {
 			if !f.Generate {
 				continue
 			}
-			generateFile(gen, f)
+			generateFile(gen, f, &all)
 		}
 		return nil
 	})
 }
 
-type errorDef struct {
-	Code        string
-	Message     string
-	ConnectCode int
-	Retryable   bool
+// serviceContract holds the declared error codes of each RPC in a service.
+type serviceContract struct {
+	Name       string
+	Procedures []procedureContract
 }
 
-func generateFile(gen *protogen.Plugin, file *protogen.File) {
-	var errors []errorDef
+// procedureContract holds the declared error codes of a single RPC.
+type procedureContract struct {
+	Procedure string
+	Codes     []string
+}
 
-	// Parse file-level error definitions (field number 50002)
-	fileOpts := file.Desc.Options()
-	if fileOpts != nil {
-		raw := fileOpts.(*descriptorpb.FileOptions)
-		if raw != nil {
-			if b, err := proto.Marshal(raw); err == nil {
-				defs := parseExtensionErrors(b, 50002)
-				errors = append(errors, defs...)
-			}
+// errorDef is a single error definition read from proto options.
+type errorDef = errordef.Def
+
+// generateFile writes the _connect_errors.go file for file. Definitions are
+// added to all, which rejects conflicts with definitions of earlier files.
+func generateFile(gen *protogen.Plugin, file *protogen.File, all *errordef.Set) {
+	var errors []errorDef
+	var sources []string
+	var fileDomain string
+	var fileCodes []string
+	var contracts []serviceContract
+
+	// Read file-level error definitions (connecterrors.v1.error)
+	// and the default error domain (connecterrors.v1.error_domain)
+	if raw, ok := file.Desc.Options().(*descriptorpb.FileOptions); ok && raw != nil {
+		defs, domain, err := errordef.FileErrors(raw)
+		if err != nil {
+			gen.Error(fmt.Errorf("%s: %w", file.Desc.Path(), err))
+			return
+		}
+		fileDomain = domain
+		errors = append(errors, defs...)
+		for _, d := range defs {
+			fileCodes = append(fileCodes, d.Code)
+			sources = append(sources, file.Desc.Path()+" (file option)")
 		}
 	}
 
-	// Parse method-level error definitions (field number 50001)
+	// Read method-level error definitions (connecterrors.v1.connect_error)
+	// and record which RPC declared which codes
 	for _, svc := range file.Services {
+		contract := serviceContract{Name: svc.GoName}
+		declared := false
 		for _, method := range svc.Methods {
-			opts := method.Desc.Options()
-			if opts == nil {
-				continue
-			}
-
-			raw := opts.(*descriptorpb.MethodOptions)
-			if raw == nil {
-				continue
+			var methodCodes []string
+			if raw, ok := method.Desc.Options().(*descriptorpb.MethodOptions); ok && raw != nil {
+				defs, err := errordef.MethodErrors(raw)
+				if err != nil {
+					gen.Error(fmt.Errorf("%s: rpc %s: %w", file.Desc.Path(), method.Desc.FullName(), err))
+					return
+				}
+				errors = append(errors, defs...)
+				for _, d := range defs {
+					methodCodes = append(methodCodes, d.Code)
+					sources = append(sources, fmt.Sprintf("%s (rpc %s)", file.Desc.Path(), method.Desc.FullName()))
+				}
 			}
 
-			b, err := proto.Marshal(raw)
-			if err != nil {
-				continue
+			// File-level errors are available to every RPC in the file.
+			// RPCs without codes keep an empty entry so that strict mode
+			// rejects every registry code they return.
+			codes := dedupeStrings(append(methodCodes, fileCodes...))
+			if len(codes) > 0 {
+				declared = true
 			}
-
-			defs := parseExtensionErrors(b, 50001)
-			errors = append(errors, defs...)
+			contract.Procedures = append(contract.Procedures, procedureContract{
+				Procedure: fmt.Sprintf("/%s/%s", svc.Desc.FullName(), method.Desc.Name()),
+				Codes:     codes,
+			})
+		}
+		if declared {
+			contracts = append(contracts, contract)
 		}
 	}
 
@@ -101,16 +140,41 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 		return
 	}
 
+	// Reject unknown placeholder types before emitting anything
+	for _, e := range errors {
+		for _, p := range errordef.Params(e.Message) {
+			if _, ok := paramTypes[p.Type]; !ok {
+				gen.Error(fmt.Errorf("%s: error %s: unknown placeholder type %q for {{%s}}", file.Desc.Path(), e.Code, p.Type, p.Name))
+				return
+			}
+		}
+	}
+
+	// Apply the file-level default domain to errors without their own
+	if fileDomain != "" {
+		for i := range errors {
+			if errors[i].Domain == "" {
+				errors[i].Domain = fileDomain
+			}
+		}
+	}
+
 	// Deduplicate errors by code (same error may appear on multiple methods)
-	seen := make(map[string]bool, len(errors))
-	unique := make([]errorDef, 0, len(errors))
+	// and fail on conflicting definitions instead of silently keeping one
+	var unique errordef.Set
+	for i, e := range errors {
+		if err := unique.Add(e, sources[i]); err != nil {
+			gen.Error(err)
+			return
+		}
+	}
+	errors = unique.Defs()
 	for _, e := range errors {
-		if !seen[e.Code] {
-			seen[e.Code] = true
-			unique = append(unique, e)
+		if err := all.Add(e, file.Desc.Path()); err != nil {
+			gen.Error(err)
+			return
 		}
 	}
-	errors = unique
 
 	filename := file.GeneratedFilenamePrefix + "_connect_errors.go"
 	g := gen.NewGeneratedFile(filename, file.GoImportPath)
@@ -119,9 +183,43 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 	g.P()
 	g.P("package ", file.GoPackageName)
 	g.P()
+	// "errors" is only needed by matchers that do not delegate to cerr.MatchError
+	needErrors := false
+	for _, e := range errors {
+		if e.Domain == "" {
+			needErrors = true
+			break
+		}
+	}
+
+	needTime, needFmt := false, false
+	for _, e := range errors {
+		if e.RetryDelay != 0 {
+			needTime = true
+		}
+		for _, p := range errordef.Params(e.Message) {
+			if paramTypes[p.Type].needTime {
+				needTime = true
+			}
+			if paramTypes[p.Type].validate {
+				needFmt = true
+			}
+		}
+	}
+
 	g.P("import (")
-	g.P(`	"errors"`)
-	g.P()
+	if needErrors {
+		g.P(`	"errors"`)
+	}
+	if needFmt {
+		g.P(`	"fmt"`)
+	}
+	if needTime {
+		g.P(`	"time"`)
+	}
+	if needErrors || needFmt || needTime {
+		g.P()
+	}
 	g.P(`	"connectrpc.com/connect"`)
 	g.P()
 	g.P(`	cerr "github.com/balcieren/connect-errors-go"`)
@@ -133,25 +231,87 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 	g.P("const (")
 	for _, e := range errors {
 		varName := "Err" + errorCodeToConstant(e.Code)
+		for _, line := range docComment(e, "Err") {
+			g.P("\t", line)
+		}
 		g.P(fmt.Sprintf("\t%s cerr.ErrorCode = %q", varName, e.Code))
 	}
 	g.P(")")
 	g.P()
 
+	// Generate per-service error contracts
+	byCode := make(map[string]errorDef, len(errors))
+	for _, e := range errors {
+		byCode[e.Code] = e
+	}
+	for _, c := range contracts {
+		varName := c.Name + "ErrorContract"
+		g.P(fmt.Sprintf("// %s lists the error codes each %s RPC declares,", varName, c.Name))
+		g.P("// including file-level errors. Enforce it with cerr.ContractInterceptor.")
+		g.P(fmt.Sprintf("var %s = cerr.Contract{", varName))
+		for _, p := range c.Procedures {
+			consts := make([]string, 0, len(p.Codes))
+			for _, code := range p.Codes {
+				consts = append(consts, codeRef(byCode[code]))
+			}
+			g.P(fmt.Sprintf("\t%q: {%s},", p.Procedure, strings.Join(consts, ", ")))
+		}
+		g.P("}")
+		g.P()
+	}
 
 	// Generate init function
 	g.P("func init() {")
 	g.P("\tcerr.RegisterAll([]cerr.Error{")
 	for _, e := range errors {
-		constName := "Err" + errorCodeToConstant(e.Code)
 		g.P("\t\t{")
-		g.P(fmt.Sprintf("\t\t\tCode:        %s,", constName))
+		g.P(fmt.Sprintf("\t\t\tCode:        %s,", codeRef(e)))
 		g.P(fmt.Sprintf("\t\t\tMessageTpl:  %q,", e.Message))
 		g.P(fmt.Sprintf("\t\t\tConnectCode: %s,", mapConnectCode(e.ConnectCode)))
 		g.P(fmt.Sprintf("\t\t\tRetryable:   %t,", e.Retryable))
+		if e.Domain != "" {
+			g.P(fmt.Sprintf("\t\t\tDomain:      %q,", e.Domain))
+		}
+		if e.RetryDelay != 0 {
+			g.P(fmt.Sprintf("\t\t\tRetryDelay:  %s,", durationLiteral(e.RetryDelay)))
+		}
+		if e.Description != "" {
+			g.P(fmt.Sprintf("\t\t\tDescription: %q,", e.Description))
+		}
+		if e.HelpURL != "" {
+			g.P(fmt.Sprintf("\t\t\tHelpURL:     %q,", e.HelpURL))
+		}
+		if e.Severity != 0 {
+			g.P(fmt.Sprintf("\t\t\tSeverity:    %s,", mapSeverity(e.Severity)))
+		}
+		if e.Deprecated {
+			g.P("\t\t\tDeprecated:  true,")
+		}
+		if e.ReplacedBy != "" {
+			g.P(fmt.Sprintf("\t\t\tReplacedBy:  %q,", e.ReplacedBy))
+		}
+		if len(e.SensitiveFields) > 0 {
+			quoted := make([]string, len(e.SensitiveFields))
+			for i, f := range e.SensitiveFields {
+				quoted[i] = strconv.Quote(f)
+			}
+			g.P(fmt.Sprintf("\t\t\tSensitiveFields: []string{%s},", strings.Join(quoted, ", ")))
+		}
 		g.P("\t\t},")
 	}
 	g.P("\t})")
+	for _, locale := range localesOf(errors) {
+		g.P(fmt.Sprintf("\tcerr.RegisterMessages(%q, map[cerr.ErrorCode]string{", locale))
+		for _, e := range errors {
+			if msg, ok := e.LocalizedMessages[locale]; ok {
+				g.P(fmt.Sprintf("\t\t%s: %q,", codeRef(e), msg))
+			}
+		}
+		g.P("\t})")
+	}
+	for _, c := range contracts {
+		g.P(fmt.Sprintf("\tcerr.RegisterContract(%sErrorContract)", c.Name))
+	}
 	g.P("}")
 	g.P()
 
@@ -159,38 +319,57 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 	g.P("// Typed constructor functions for compile-time safe error creation.")
 	g.P("// Parameters are derived from {{placeholder}} fields in message templates.")
 	for _, e := range errors {
-		constName := "Err" + errorCodeToConstant(e.Code)
+		constName := codeRef(e)
 		baseName := errorCodeToConstant(e.Code)
 		funcName := "NewErr" + baseName
-		fields := extractTemplateFields(e.Message)
+		params := errordef.Params(e.Message)
 
-		if len(fields) == 0 {
+		if len(params) == 0 {
 			// No placeholders → no-arg constructor
 			g.P(fmt.Sprintf("// %s creates a *connect.Error for %s.", funcName, e.Code))
-			g.P(fmt.Sprintf("func %s() *connect.Error {", funcName))
-			g.P(fmt.Sprintf("\treturn cerr.New(%s, nil)", constName))
+			printDeprecated(g, e, "NewErr")
+			g.P(fmt.Sprintf("func %s(opts ...cerr.Option) *connect.Error {", funcName))
+			g.P(fmt.Sprintf("\treturn cerr.New(%s, nil, opts...)", constName))
 			g.P("}")
 		} else {
 			// Generate params struct
 			structName := baseName + "Params"
 			g.P(fmt.Sprintf("// %s holds the template fields for %s.", structName, e.Code))
 			g.P(fmt.Sprintf("type %s struct {", structName))
-			for _, f := range fields {
-				g.P(fmt.Sprintf("\t%s string", fieldToExportedName(f)))
+			for _, p := range params {
+				field := fmt.Sprintf("\t%s %s", fieldToExportedName(p.Name), paramTypes[p.Type].goType)
+				if slices.Contains(e.SensitiveFields, p.Name) {
+					field += " // Sensitive: masked in the client-visible message."
+				}
+				g.P(field)
 			}
 			g.P("}")
 			g.P()
 
 			// Generate constructor
 			g.P(fmt.Sprintf("// %s creates a *connect.Error for %s.", funcName, e.Code))
-			g.P(fmt.Sprintf("func %s(p %s) *connect.Error {", funcName, structName))
+			printDeprecated(g, e, "NewErr")
+			g.P(fmt.Sprintf("func %s(p %s, opts ...cerr.Option) *connect.Error {", funcName, structName))
+
+			// Reject values the field type cannot rule out, like cerr.New
+			// rejects unknown codes
+			for _, p := range params {
+				pt := paramTypes[p.Type]
+				if !pt.validate {
+					continue
+				}
+				g.P(fmt.Sprintf("\tif _, err := %s; err != nil {", fmt.Sprintf(pt.parse, "p."+fieldToExportedName(p.Name))))
+				g.P(fmt.Sprintf("\t\treturn connect.NewError(connect.CodeInternal, fmt.Errorf(\"%s: %s: %%w\", err))", e.Code, p.Name))
+				g.P("\t}")
+			}
 
 			// Build cerr.M{} from struct fields
 			var mapEntries []string
-			for _, f := range fields {
-				mapEntries = append(mapEntries, fmt.Sprintf("%q: p.%s", f, fieldToExportedName(f)))
+			for _, p := range params {
+				value := fmt.Sprintf(paramTypes[p.Type].format, "p."+fieldToExportedName(p.Name))
+				mapEntries = append(mapEntries, fmt.Sprintf("%q: %s", p.Name, value))
 			}
-			g.P(fmt.Sprintf("\treturn cerr.New(%s, cerr.M{%s})", constName, strings.Join(mapEntries, ", ")))
+			g.P(fmt.Sprintf("\treturn cerr.New(%s, cerr.M{%s}, opts...)", constName, strings.Join(mapEntries, ", ")))
 			g.P("}")
 		}
 		g.P()
@@ -201,10 +380,17 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 	g.P("// They check both metadata headers and protobuf details for compatibility.")
 	for _, e := range errors {
 		baseName := errorCodeToConstant(e.Code)
-		constName := "Err" + baseName
+		constName := codeRef(e)
 		funcName := "Is" + baseName
 		g.P(fmt.Sprintf("// %s reports whether err is a %s error.", funcName, e.Code))
 		g.P(fmt.Sprintf("func %s(err error) bool {", funcName))
+		if e.Domain != "" {
+			// Match on the (domain, reason) pair of the ErrorInfo detail
+			g.P(fmt.Sprintf("\treturn cerr.MatchError(err, %q, %s)", e.Domain, constName))
+			g.P("}")
+			g.P()
+			continue
+		}
 		g.P("\tvar connectErr *connect.Error")
 		g.P("\tif !errors.As(err, &connectErr) {")
 		g.P("\t\treturn false")
@@ -222,103 +408,162 @@ func generateFile(gen *protogen.Plugin, file *protogen.File) {
 		g.P("}")
 		g.P()
 	}
-}
 
-// parseExtensionErrors extracts ErrorDef messages from wire-format options
-// by looking for the specified field number.
-func parseExtensionErrors(b []byte, fieldNum protowire.Number) []errorDef {
-	var defs []errorDef
-	for len(b) > 0 {
-		num, wtype, n := protowire.ConsumeTag(b)
-		if n < 0 {
-			break
+	// Generate client-side AsXxx decoders for errors with template fields
+	headerDone := false
+	for _, e := range errors {
+		params := errordef.Params(e.Message)
+		if len(params) == 0 {
+			continue
 		}
-		b = b[n:]
-
-		switch wtype {
-		case protowire.VarintType:
-			_, n = protowire.ConsumeVarint(b)
-		case protowire.Fixed32Type:
-			_, n = protowire.ConsumeFixed32(b)
-		case protowire.Fixed64Type:
-			_, n = protowire.ConsumeFixed64(b)
-		case protowire.BytesType:
-			v, vn := protowire.ConsumeBytes(b)
-			n = vn
-			if num == fieldNum && n > 0 {
-				if def, ok := parseErrorDef(v); ok {
-					defs = append(defs, def)
-				}
+		if !headerDone {
+			g.P("// Client-side decoders that reconstruct typed params from the ErrorInfo metadata.")
+			headerDone = true
+		}
+		baseName := errorCodeToConstant(e.Code)
+		structName := baseName + "Params"
+		funcName := "As" + baseName
+		g.P(fmt.Sprintf("// %s reports whether err is a %s error and returns its template parameters.", funcName, e.Code))
+		g.P(fmt.Sprintf("func %s(err error) (%s, bool) {", funcName, structName))
+		g.P(fmt.Sprintf("\tif !Is%s(err) {", baseName))
+		g.P(fmt.Sprintf("\t\treturn %s{}, false", structName))
+		g.P("\t}")
+		g.P("\tdata := cerr.ErrorData(err)")
+
+		// String fields are copied as-is; typed fields are parsed below and a
+		// malformed value makes the decoder report false.
+		var typed []errordef.Param
+		g.P(fmt.Sprintf("\tp := %s{", structName))
+		for _, p := range params {
+			if paramTypes[p.Type].parse != "%s" {
+				typed = append(typed, p)
+				continue
 			}
-		case protowire.StartGroupType:
-			_, n = protowire.ConsumeGroup(num, b)
-		default:
-			return defs
+			g.P(fmt.Sprintf("\t\t%s: data[%q],", fieldToExportedName(p.Name), p.Name))
 		}
-
-		if n < 0 {
-			break
+		g.P("\t}")
+		if len(typed) > 0 {
+			g.P("\tvar parseErr error")
+		}
+		for _, p := range typed {
+			field := "p." + fieldToExportedName(p.Name)
+			value := fmt.Sprintf(paramTypes[p.Type].parse, fmt.Sprintf("data[%q]", p.Name))
+			if slices.Contains(e.SensitiveFields, p.Name) {
+				// Sensitive values are left out of the metadata; keep the zero value.
+				g.P(fmt.Sprintf("\tif _, ok := data[%q]; ok {", p.Name))
+				g.P(fmt.Sprintf("\t\tif %s, parseErr = %s; parseErr != nil {", field, value))
+				g.P(fmt.Sprintf("\t\t\treturn %s{}, false", structName))
+				g.P("\t\t}")
+				g.P("\t}")
+				continue
+			}
+			g.P(fmt.Sprintf("\tif %s, parseErr = %s; parseErr != nil {", field, value))
+			g.P(fmt.Sprintf("\t\treturn %s{}, false", structName))
+			g.P("\t}")
 		}
-		b = b[n:]
+		g.P("\treturn p, true")
+		g.P("}")
+		g.P()
 	}
-	return defs
 }
 
-// parseErrorDef parses a single ErrorDef message from wire-format bytes.
-// ErrorDef fields: code(1), message(2), connect_code(3), retryable(4).
-func parseErrorDef(b []byte) (errorDef, bool) {
-	var def errorDef
-	var found bool
-	for len(b) > 0 {
-		num, wtype, n := protowire.ConsumeTag(b)
-		if n < 0 {
-			break
+// codeRef returns the Go expression naming the code of e in generated code:
+// its ErrXxx constant, or a cerr.ErrorCode conversion of the code if e is
+// deprecated, so that contracts, registrations and matchers do not use a
+// deprecated identifier (staticcheck SA1019).
+func codeRef(e errorDef) string {
+	if e.Deprecated {
+		return fmt.Sprintf("cerr.ErrorCode(%q)", e.Code)
+	}
+	return "Err" + errorCodeToConstant(e.Code)
+}
+
+// docComment returns the doc comment lines of the constant for e: a sentence
+// starting with the constant name, the description and a Deprecated:
+// paragraph. It returns nil if e has neither a description nor a deprecation.
+// prefix is prepended to the constant names, e.g. "Err" → "ErrUserMissing".
+func docComment(e errorDef, prefix string) []string {
+	if e.Description == "" && !e.Deprecated {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("// %s%s is the %s error code.", prefix, errorCodeToConstant(e.Code), e.Code)}
+	if e.Description != "" {
+		lines = append(lines, "//")
+		for _, l := range strings.Split(strings.TrimSpace(e.Description), "\n") {
+			lines = append(lines, strings.TrimRight("// "+strings.TrimSpace(l), " "))
 		}
-		b = b[n:]
-
-		switch wtype {
-		case protowire.BytesType:
-			v, vn := protowire.ConsumeBytes(b)
-			n = vn
-			switch num {
-			case 1:
-				def.Code = string(v)
-				found = true
-			case 2:
-				def.Message = string(v)
-				found = true
-			}
-		case protowire.VarintType:
-			v, vn := protowire.ConsumeVarint(b)
-			n = vn
-			switch num {
-			case 3:
-				def.ConnectCode = int(v)
-				found = true
-			case 4:
-				def.Retryable = v != 0
-				found = true
-			}
-		default:
-			// Skip unknown wire types
-			switch wtype {
-			case protowire.Fixed32Type:
-				_, n = protowire.ConsumeFixed32(b)
-			case protowire.Fixed64Type:
-				_, n = protowire.ConsumeFixed64(b)
-			case protowire.StartGroupType:
-				_, n = protowire.ConsumeGroup(num, b)
-			default:
-				return def, false
+	}
+	if e.Deprecated {
+		lines = append(lines, "//", deprecatedLine(e, prefix))
+	}
+	return lines
+}
+
+// localesOf returns the sorted locales that have a localized message in any of errors.
+func localesOf(errors []errorDef) []string {
+	seen := make(map[string]bool)
+	var locales []string
+	for _, e := range errors {
+		for locale := range e.LocalizedMessages {
+			if !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
 			}
 		}
+	}
+	sort.Strings(locales)
+	return locales
+}
 
-		if n < 0 {
-			break
+// printDeprecated emits a Deprecated: paragraph for a generated function if e is deprecated.
+func printDeprecated(g *protogen.GeneratedFile, e errorDef, prefix string) {
+	if e.Deprecated {
+		g.P("//")
+		g.P(deprecatedLine(e, prefix))
+	}
+}
+
+// deprecatedLine returns the "// Deprecated:" line for e, naming the
+// replacement with the given identifier prefix if there is one.
+func deprecatedLine(e errorDef, prefix string) string {
+	if e.ReplacedBy != "" {
+		return fmt.Sprintf("// Deprecated: Use %s%s instead.", prefix, errorCodeToConstant(e.ReplacedBy))
+	}
+	return fmt.Sprintf("// Deprecated: %s is deprecated.", e.Code)
+}
+
+// durationLiteral renders d as a Go expression using the largest exact time unit,
+// e.g. 30*time.Second → "30 * time.Second".
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
 		}
-		b = b[n:]
 	}
-	return def, found
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// dedupeStrings returns ss without duplicates, preserving first-seen order.
+func dedupeStrings(ss []string) []string {
+	seen := make(map[string]bool, len(ss))
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 func errorCodeToConstant(code string) string {
@@ -333,31 +578,29 @@ func errorCodeToConstant(code string) string {
 	return result
 }
 
-// extractTemplateFields parses {{placeholder}} names from a message template.
-// Returns unique fields in order of first appearance.
-func extractTemplateFields(message string) []string {
-	var fields []string
-	seen := make(map[string]bool)
-	i := 0
-	for i < len(message) {
-		start := strings.Index(message[i:], "{{")
-		if start == -1 {
-			break
-		}
-		start += i
-		end := strings.Index(message[start:], "}}")
-		if end == -1 {
-			break
-		}
-		end += start
-		field := message[start+2 : end]
-		if field != "" && !seen[field] {
-			seen[field] = true
-			fields = append(fields, field)
-		}
-		i = end + 2
-	}
-	return fields
+// paramType describes how a placeholder type maps to Go code.
+// format and parse are fmt patterns wrapping the value expression; a parse
+// pattern other than "%s" calls a helper returning (value, error). validate
+// is set for types whose Go type admits invalid values: NewErrXxx rejects
+// them with its parse helper.
+type paramType struct {
+	goType   string
+	format   string
+	parse    string
+	validate bool
+	needTime bool
+}
+
+// paramTypes maps placeholder types to their generated Go representation.
+// The runtime counterparts live in the connecterrors package (FormatXxx/ParseXxx).
+var paramTypes = map[string]paramType{
+	"":          {goType: "string", format: "%s", parse: "%s"},
+	"string":    {goType: "string", format: "%s", parse: "%s"},
+	"int":       {goType: "int64", format: "cerr.FormatInt(%s)", parse: "cerr.ParseInt(%s)"},
+	"decimal":   {goType: "string", format: "%s", parse: "cerr.ParseDecimal(%s)", validate: true},
+	"bool":      {goType: "bool", format: "cerr.FormatBool(%s)", parse: "cerr.ParseBool(%s)"},
+	"timestamp": {goType: "time.Time", format: "cerr.FormatTimestamp(%s)", parse: "cerr.ParseTimestamp(%s)", needTime: true},
+	"duration":  {goType: "time.Duration", format: "cerr.FormatDuration(%s)", parse: "cerr.ParseDuration(%s)", needTime: true},
 }
 
 // fieldToExportedName converts a snake_case template field to a PascalCase Go exported name.
@@ -374,6 +617,20 @@ func fieldToExportedName(field string) string {
 	return result
 }
 
+// mapSeverity returns the cerr.Severity constant for a connecterrors.v1.Severity value.
+func mapSeverity(severity int) string {
+	switch severity {
+	case 1:
+		return "cerr.SeverityWarning"
+	case 2:
+		return "cerr.SeverityError"
+	case 3:
+		return "cerr.SeverityCritical"
+	default:
+		return "cerr.SeverityUnspecified"
+	}
+}
+
 func mapConnectCode(code int) string {
 	m := map[int]string{
 		1:  "connect.CodeCanceled",
@@ -1,36 +1,22 @@
 package main
 
 import (
-	"reflect"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
-)
+	"time"
 
-func TestExtractTemplateFields(t *testing.T) {
-	tests := []struct {
-		name    string
-		message string
-		want    []string
-	}{
-		{"no placeholders", "Internal server error", nil},
-		{"single field", "User '{{id}}' not found", []string{"id"}},
-		{"multiple fields", "User '{{id}}' not found in '{{org}}'", []string{"id", "org"}},
-		{"duplicate fields", "{{id}} and {{id}} again", []string{"id"}},
-		{"snake_case field", "Product {{product_id}} unavailable", []string{"product_id"}},
-		{"empty message", "", nil},
-		{"adjacent placeholders", "{{a}}{{b}}", []string{"a", "b"}},
-		{"unclosed placeholder", "Hello {{name", nil},
-		{"three fields", "{{amount}} exceeds {{limit}} for {{account}}", []string{"amount", "limit", "account"}},
-	}
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/pluginpb"
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := extractTemplateFields(tt.message)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("extractTemplateFields(%q) = %v, want %v", tt.message, got, tt.want)
-			}
-		})
-	}
-}
+	"github.com/balcieren/connect-errors-go/internal/errordef"
+	connecterrorspb "github.com/balcieren/connect-errors-go/proto/connecterrors"
+)
 
 func TestFieldToExportedName(t *testing.T) {
 	tests := []struct {
@@ -114,36 +100,506 @@ func TestMapConnectCode(t *testing.T) {
 	}
 }
 
-func TestParseErrorDef(t *testing.T) {
-	// Manual protobuf wire format construction
-	// ErrorDef {
-	//   code (1): "ERR_TEST"
-	//   message (2): "msg"
-	//   connect_code (3): 5 (not_found)
-	//   retryable (4): true
-	// }
-	data := []byte{
-		0x0a, 0x08, 'E', 'R', 'R', '_', 'T', 'E', 'S', 'T', // tag 1 (string): "ERR_TEST"
-		0x12, 0x03, 'm', 's', 'g', // tag 2 (string): "msg"
-		0x18, 0x05, // tag 3 (varint): 5
-		0x20, 0x01, // tag 4 (varint): 1
+func TestGenerateFileDomain(t *testing.T) {
+	other := errorDefForTest("ERROR_OTHER", "Other", connecterrorspb.Code_CODE_INTERNAL)
+	other.Domain = "other.acme.com"
+	fileOpts := fileOptionsForTest("example.com/testv1;testv1",
+		errorDefForTest("ERROR_CARD_DECLINED", "Card declined", connecterrorspb.Code_CODE_FAILED_PRECONDITION),
+		other,
+	)
+	proto.SetExtension(fileOpts, connecterrorspb.E_ErrorDomain, "payments.acme.com")
+
+	out := generateForTest(t, fileOpts)
+
+	for _, want := range []string{
+		`Domain:      "payments.acme.com",`,
+		`Domain:      "other.acme.com",`,
+		`return cerr.MatchError(err, "payments.acme.com", ErrCardDeclined)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"errors"`) {
+		t.Error("generated code should not import errors when all matchers use MatchError")
+	}
+}
+
+// errorDefForTest returns an ErrorDef with the given code, message and Connect code.
+func errorDefForTest(code, message string, connectCode connecterrorspb.Code) *connecterrorspb.ErrorDef {
+	return &connecterrorspb.ErrorDef{Code: code, Message: message, ConnectCode: connectCode}
+}
+
+// fileOptionsForTest returns file options with the given go_package and
+// file-level error definitions.
+func fileOptionsForTest(goPackage string, defs ...*connecterrorspb.ErrorDef) *descriptorpb.FileOptions {
+	opts := &descriptorpb.FileOptions{GoPackage: proto.String(goPackage)}
+	if len(defs) > 0 {
+		proto.SetExtension(opts, connecterrorspb.E_Error, defs)
+	}
+	return opts
+}
+
+// generateForTest runs generateFile on a single test.proto with the given
+// file options and returns the generated Go source.
+func generateForTest(t *testing.T, fileOpts *descriptorpb.FileOptions) string {
+	t.Helper()
+	return generateProtoForTest(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: fileOpts,
+	})
+}
+
+// generateProtoForTest runs generateFile on fdp and returns the generated Go source.
+func generateProtoForTest(t *testing.T, fdp *descriptorpb.FileDescriptorProto) string {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fdp.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fdp},
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all errordef.Set
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f, &all)
+		}
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+	if len(resp.File) != 1 {
+		t.Fatalf("generated %d files, want 1", len(resp.File))
+	}
+	return resp.File[0].GetContent()
+}
+
+func TestDurationLiteral(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{2 * time.Hour, "2 * time.Hour"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{30 * time.Second, "30 * time.Second"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond"},
+		{7, "time.Duration(7)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := durationLiteral(tt.d); got != tt.want {
+				t.Errorf("durationLiteral(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFileRetryDelay(t *testing.T) {
+	def := errorDefForTest("ERROR_RATE_LIMITED", "Slow down", connecterrorspb.Code_CODE_RESOURCE_EXHAUSTED)
+	def.RetryDelay = durationpb.New(30 * time.Second)
+
+	out := generateForTest(t, fileOptionsForTest("example.com/testv1;testv1", def))
+	for _, want := range []string{`"time"`, "RetryDelay:  30 * time.Second,"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateFileConstructorOptions(t *testing.T) {
+	out := generateForTest(t, fileOptionsForTest("example.com/testv1;testv1",
+		errorDefForTest("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", connecterrorspb.Code_CODE_NOT_FOUND),
+		errorDefForTest("ERROR_RATE_LIMITED", "Slow down", connecterrorspb.Code_CODE_RESOURCE_EXHAUSTED),
+	))
+	for _, want := range []string{
+		"func NewErrUserNotFound(p UserNotFoundParams, opts ...cerr.Option) *connect.Error {",
+		`return cerr.New(ErrUserNotFound, cerr.M{"id": p.Id}, opts...)`,
+		"func NewErrRateLimited(opts ...cerr.Option) *connect.Error {",
+		"return cerr.New(ErrRateLimited, nil, opts...)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateFileDecoders(t *testing.T) {
+	out := generateForTest(t, fileOptionsForTest("example.com/testv1;testv1",
+		errorDefForTest("ERROR_ACCOUNT_LOCKED", "Account '{{email}}' locked until {{unlock_at}}", connecterrorspb.Code_CODE_PERMISSION_DENIED),
+		errorDefForTest("ERROR_RATE_LIMITED", "Slow down", connecterrorspb.Code_CODE_RESOURCE_EXHAUSTED),
+	))
+	for _, want := range []string{
+		"func AsAccountLocked(err error) (AccountLockedParams, bool) {",
+		"if !IsAccountLocked(err) {",
+		"data := cerr.ErrorData(err)",
+		`Email:    data["email"],`,
+		`UnlockAt: data["unlock_at"],`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "func AsRateLimited(") {
+		t.Error("errors without template fields should not get an AsXxx decoder")
+	}
+}
+
+func TestGenerateFileTypedParams(t *testing.T) {
+	out := generateForTest(t, fileOptionsForTest("example.com/testv1;testv1",
+		errorDefForTest("ERROR_INSUFFICIENT_FUNDS", "Requested {{amount:decimal}}, {{count:int}} tries, locked until {{unlock_at:timestamp}}", connecterrorspb.Code_CODE_FAILED_PRECONDITION),
+	))
+	for _, want := range []string{
+		`"time"`,
+		"Amount   string",
+		"Count    int64",
+		"UnlockAt time.Time",
+		`"amount": p.Amount`,
+		`"unlock_at": cerr.FormatTimestamp(p.UnlockAt)`,
+		`if p.Amount, parseErr = cerr.ParseDecimal(data["amount"]); parseErr != nil {`,
+		`if p.Count, parseErr = cerr.ParseInt(data["count"]); parseErr != nil {`,
+		"return InsufficientFundsParams{}, false",
+		`"fmt"`,
+		"if _, err := cerr.ParseDecimal(p.Amount); err != nil {\n\t\treturn connect.NewError(connect.CodeInternal, fmt.Errorf(\"ERROR_INSUFFICIENT_FUNDS: amount: %w\", err))\n\t}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestGenerateFileDecodersMalformedParams compiles the generated code and
+// checks that AsXxx rejects metadata values that do not parse.
+func TestGenerateFileDecodersMalformedParams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go run in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	out := generateForTest(t, fileOptionsForTest("example.com/testv1;main",
+		errorDefForTest("ERROR_INSUFFICIENT_FUNDS", "Requested {{amount:decimal}} by {{owner}}, {{count:int}} tries", connecterrorspb.Code_CODE_FAILED_PRECONDITION),
+	))
+
+	const mainSrc = `package main
+
+import (
+	"fmt"
+
+	cerr "github.com/balcieren/connect-errors-go"
+)
+
+func main() {
+	for _, data := range []cerr.M{
+		{"amount": "12.50", "owner": "alice", "count": "3"},
+		{"amount": "12.50", "owner": "alice", "count": "many"},
+		{"amount": "lots", "owner": "alice", "count": "3"},
+	} {
+		p, ok := AsInsufficientFunds(cerr.New(ErrInsufficientFunds, data))
+		fmt.Printf("%t %+v\n", ok, p)
+	}
+	fmt.Println(NewErrInsufficientFunds(InsufficientFundsParams{Amount: "lots", Owner: "bob", Count: 1}))
+}
+`
+	dir := goModuleForTest(t)
+	if err := os.WriteFile(filepath.Join(dir, "test.connect_errors.go"), []byte(out), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(mainSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	got, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, got)
+	}
+	want := "true {Amount:12.50 Owner:alice Count:3}\n" +
+		"false {Amount: Owner: Count:0}\n" +
+		"false {Amount: Owner: Count:0}\n" +
+		"internal: ERROR_INSUFFICIENT_FUNDS: amount: connecterrors: invalid decimal \"lots\"\n"
+	if string(got) != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// goModuleForTest creates a module in a temporary directory that resolves the
+// runtime package to this checkout. It reuses the requirements and checksums
+// of the repository's go.mod, so building it needs no network access.
+func goModuleForTest(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, requires, _ := strings.Cut(string(rootMod), "\n")
+	mod := "module example.com/decodetest\n" + requires +
+		"\nrequire github.com/balcieren/connect-errors-go v0.0.0\n" +
+		"\nreplace github.com/balcieren/connect-errors-go => " + root + "\n"
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerateFileUnknownParamType(t *testing.T) {
+	fileOpts := fileOptionsForTest("example.com/testv1;testv1",
+		errorDefForTest("ERROR_BAD", "{{x:money}}", connecterrorspb.Code_CODE_INTERNAL),
+	)
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("test.proto"),
+			Package: proto.String("test.v1"),
+			Syntax:  proto.String("proto3"),
+			Options: fileOpts,
+		}},
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generateFile(gen, gen.Files[0], &errordef.Set{})
+	if resp := gen.Response(); resp.Error == nil || !strings.Contains(resp.GetError(), `unknown placeholder type "money"`) {
+		t.Errorf("expected unknown placeholder type error, got %v", resp.Error)
+	}
+}
+
+// methodForTest builds a method descriptor with the given method-level error definitions.
+func methodForTest(name string, defs ...*connecterrorspb.ErrorDef) *descriptorpb.MethodDescriptorProto {
+	opts := &descriptorpb.MethodOptions{}
+	if len(defs) > 0 {
+		proto.SetExtension(opts, connecterrorspb.E_ConnectError, defs)
+	}
+	return &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(".test.v1.Msg"),
+		OutputType: proto.String(".test.v1.Msg"),
+		Options:    opts,
+	}
+}
+
+// serviceProtoForTest builds a test.proto file with a UserService and file-level errors.
+func serviceProtoForTest(fileDefs []*connecterrorspb.ErrorDef, methods ...*descriptorpb.MethodDescriptorProto) *descriptorpb.FileDescriptorProto {
+	fileOpts := fileOptionsForTest("example.com/testv1;testv1", fileDefs...)
+	return &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test.proto"),
+		Package:     proto.String("test.v1"),
+		Syntax:      proto.String("proto3"),
+		Options:     fileOpts,
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Msg")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:   proto.String("UserService"),
+			Method: methods,
+		}},
+	}
+}
+
+func TestGenerateFileContract(t *testing.T) {
+	fdp := serviceProtoForTest(
+		[]*connecterrorspb.ErrorDef{errorDefForTest("ERROR_UNAUTHORIZED", "Authentication required", connecterrorspb.Code_CODE_UNAUTHENTICATED)},
+		methodForTest("GetUser", errorDefForTest("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", connecterrorspb.Code_CODE_NOT_FOUND)),
+		methodForTest("DeleteUser", errorDefForTest("ERROR_DELETE_FORBIDDEN", "Forbidden", connecterrorspb.Code_CODE_PERMISSION_DENIED)),
+	)
+
+	out := generateProtoForTest(t, fdp)
+	for _, want := range []string{
+		"var UserServiceErrorContract = cerr.Contract{",
+		`"/test.v1.UserService/GetUser":    {ErrUserNotFound, ErrUnauthorized},`,
+		`"/test.v1.UserService/DeleteUser": {ErrDeleteForbidden, ErrUnauthorized},`,
+		"cerr.RegisterContract(UserServiceErrorContract)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateFileContractRPCWithoutCodes(t *testing.T) {
+	fdp := serviceProtoForTest(nil,
+		methodForTest("GetUser", errorDefForTest("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", connecterrorspb.Code_CODE_NOT_FOUND)),
+		methodForTest("Ping"),
+	)
+
+	out := generateProtoForTest(t, fdp)
+	for _, want := range []string{
+		`"/test.v1.UserService/GetUser": {ErrUserNotFound},`,
+		`"/test.v1.UserService/Ping":    {},`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated code missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateFileConflictingDefinitions(t *testing.T) {
+	fdp := serviceProtoForTest(nil,
+		methodForTest("GetUser", errorDefForTest("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", connecterrorspb.Code_CODE_NOT_FOUND)),
+		methodForTest("DeleteUser", errorDefForTest("ERROR_USER_NOT_FOUND", "No such user", connecterrorspb.Code_CODE_NOT_FOUND)),
+	)
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fdp},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generateFile(gen, gen.Files[0], &errordef.Set{})
+
+	msg := gen.Response().GetError()
+	for _, want := range []string{"ERROR_USER_NOT_FOUND", "test.v1.UserService.GetUser", "test.v1.UserService.DeleteUser", "message"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestGenerateFileConflictAcrossFiles(t *testing.T) {
+	a := serviceProtoForTest([]*connecterrorspb.ErrorDef{errorDefForTest("ERROR_SHARED", "Shared", connecterrorspb.Code_CODE_NOT_FOUND)})
+	b := serviceProtoForTest([]*connecterrorspb.ErrorDef{errorDefForTest("ERROR_SHARED", "Shared", connecterrorspb.Code_CODE_INTERNAL)})
+	b.Name = proto.String("other.proto")
+	b.Package = proto.String("other.v1")
+	b.Options.GoPackage = proto.String("example.com/otherv1;otherv1")
+
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto", "other.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{a, b},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all errordef.Set
+	for _, f := range gen.Files {
+		generateFile(gen, f, &all)
+	}
+	if msg := gen.Response().GetError(); !strings.Contains(msg, "connect_code") || !strings.Contains(msg, "other.proto") {
+		t.Errorf("expected cross-file connect_code conflict, got %q", msg)
+	}
+}
+
+func TestGenerateFileIdenticalDuplicates(t *testing.T) {
+	def := errorDefForTest("ERROR_USER_NOT_FOUND", "User '{{id}}' not found", connecterrorspb.Code_CODE_NOT_FOUND)
+	code := generateProtoForTest(t, serviceProtoForTest(nil,
+		methodForTest("GetUser", def),
+		methodForTest("DeleteUser", def),
+	))
+	if n := strings.Count(code, "ErrUserNotFound cerr.ErrorCode"); n != 1 {
+		t.Errorf("ErrUserNotFound declared %d times, want 1", n)
+	}
+}
+
+func TestGenerateFileInvalidDefinition(t *testing.T) {
+	fdp := serviceProtoForTest(nil, methodForTest("GetUser", errorDefForTest("user-not-found", "User not found", connecterrorspb.Code_CODE_NOT_FOUND)))
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fdp},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generateFile(gen, gen.Files[0], &errordef.Set{})
+
+	msg := gen.Response().GetError()
+	if !strings.Contains(msg, "test.v1.UserService.GetUser") || !strings.Contains(msg, `invalid error code "user-not-found"`) {
+		t.Errorf("error = %q, want invalid code diagnostic with location", msg)
+	}
+}
+
+func TestGenerateFileDocsAndDeprecation(t *testing.T) {
+	oldDef := &connecterrorspb.ErrorDef{
+		Code:        "ERROR_USER_GONE",
+		Message:     "User gone",
+		ConnectCode: connecterrorspb.Code_CODE_NOT_FOUND,
+		Description: "Returned when the user was deleted.\nRetrying will not help.",
+		HelpUrl:     "https://docs.acme.com/errors/user-gone",
+		Severity:    connecterrorspb.Severity_SEVERITY_WARNING,
+		Deprecated:  true,
+		ReplacedBy:  "ERROR_USER_NOT_FOUND",
 	}
+	code := generateProtoForTest(t, serviceProtoForTest([]*connecterrorspb.ErrorDef{
+		oldDef,
+		errorDefForTest("ERROR_USER_NOT_FOUND", "User not found", connecterrorspb.Code_CODE_NOT_FOUND),
+	}, methodForTest("GetUser")))
 
-	got, ok := parseErrorDef(data)
-	if !ok {
-		t.Fatal("parseErrorDef failed")
+	for _, want := range []string{
+		"\t// ErrUserGone is the ERROR_USER_GONE error code.\n\t//\n\t// Returned when the user was deleted.\n\t// Retrying will not help.\n\t//\n\t// Deprecated: Use ErrUserNotFound instead.\n\tErrUserGone ",
+		// Deprecated constants are only referenced by deprecated code.
+		`"/test.v1.UserService/GetUser": {cerr.ErrorCode("ERROR_USER_GONE"), ErrUserNotFound},`,
+		`Code:        cerr.ErrorCode("ERROR_USER_GONE"),`,
+		`return cerr.New(cerr.ErrorCode("ERROR_USER_GONE"), nil, opts...)`,
+		`Description: "Returned when the user was deleted.\nRetrying will not help.",`,
+		`HelpURL:     "https://docs.acme.com/errors/user-gone",`,
+		"Severity:    cerr.SeverityWarning,",
+		"Deprecated:  true,",
+		`ReplacedBy:  "ERROR_USER_NOT_FOUND",`,
+		"// NewErrUserGone creates a *connect.Error for ERROR_USER_GONE.\n//\n// Deprecated: Use NewErrUserNotFound instead.\nfunc NewErrUserGone(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
 	}
+	if strings.Contains(code, "// Deprecated: Use NewErrUserGone") {
+		t.Error("non-deprecated constructor marked deprecated")
+	}
+}
 
-	if got.Code != "ERR_TEST" {
-		t.Errorf("Code = %q, want ERR_TEST", got.Code)
+func TestGenerateFileLocalizedMessages(t *testing.T) {
+	def := &connecterrorspb.ErrorDef{
+		Code:        "ERROR_USER_NOT_FOUND",
+		Message:     "User '{{id}}' not found",
+		ConnectCode: connecterrorspb.Code_CODE_NOT_FOUND,
+		LocalizedMessages: map[string]string{
+			"de":    "Benutzer '{{id}}' nicht gefunden",
+			"pt-BR": "Usuário '{{id}}' não encontrado",
+		},
 	}
-	if got.Message != "msg" {
-		t.Errorf("Message = %q, want msg", got.Message)
+	code := generateProtoForTest(t, serviceProtoForTest([]*connecterrorspb.ErrorDef{def}))
+
+	want := "\tcerr.RegisterMessages(\"de\", map[cerr.ErrorCode]string{\n\t\tErrUserNotFound: \"Benutzer '{{id}}' nicht gefunden\",\n\t})\n" +
+		"\tcerr.RegisterMessages(\"pt-BR\", map[cerr.ErrorCode]string{\n\t\tErrUserNotFound: \"Usuário '{{id}}' não encontrado\",\n\t})\n"
+	if !strings.Contains(code, want) {
+		t.Errorf("generated code missing %q\n%s", want, code)
 	}
-	if got.ConnectCode != 5 {
-		t.Errorf("ConnectCode = %d, want 5", got.ConnectCode)
+}
+
+func TestGenerateFileSensitiveFields(t *testing.T) {
+	def := &connecterrorspb.ErrorDef{
+		Code:            "ERROR_INVALID_CREDENTIALS",
+		Message:         "Invalid credentials for user '{{email}}' from {{ip}}",
+		ConnectCode:     connecterrorspb.Code_CODE_UNAUTHENTICATED,
+		SensitiveFields: []string{"email", "ip"},
 	}
-	if !got.Retryable {
-		t.Error("Retryable = false, want true")
+	code := generateProtoForTest(t, serviceProtoForTest([]*connecterrorspb.ErrorDef{def}))
+
+	for _, want := range []string{
+		`SensitiveFields: []string{"email", "ip"},`,
+		"Email string // Sensitive: masked in the client-visible message.",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
 	}
 }
@@ -0,0 +1,547 @@
+// protoc-gen-connect-errors-ts is a protoc plugin that generates TypeScript
+// error constants, matchers and typed param decoders for connect-es clients
+// from the same proto error definitions as protoc-gen-connect-errors-go.
+//
+// Usage:
+//
+//	protoc --connect-errors-ts_out=gen/ts proto/*.proto
+//
+// Unlike the Go plugin it does not require go_package to be set. Files are
+// always placed next to their proto path; the standard paths option and the
+// protoc-gen-es options in ignoredParams are accepted and ignored.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/balcieren/connect-errors-go/internal/errordef"
+)
+
+var version = "0.4.0"
+
+func main() {
+	showVersion := flag.Bool("version", false, "print version")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Fprintf(os.Stderr, "protoc-gen-connect-errors-ts %s\n", version)
+		os.Exit(0)
+	}
+
+	in, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "protoc-gen-connect-errors-ts: %v\n", err)
+		os.Exit(1)
+	}
+	req := &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(in, req); err != nil {
+		fmt.Fprintf(os.Stderr, "protoc-gen-connect-errors-ts: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := proto.Marshal(generate(req))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "protoc-gen-connect-errors-ts: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := os.Stdout.Write(out); err != nil {
+		fmt.Fprintf(os.Stderr, "protoc-gen-connect-errors-ts: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate produces one _connect_errors.ts file for every requested proto file
+// that declares errors.
+func generate(req *pluginpb.CodeGeneratorRequest) *pluginpb.CodeGeneratorResponse {
+	resp := &pluginpb.CodeGeneratorResponse{
+		SupportedFeatures: proto.Uint64(uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)),
+	}
+	for _, param := range strings.Split(req.GetParameter(), ",") {
+		key, _, _ := strings.Cut(param, "=")
+		if param != "" && !ignoredParams[key] {
+			resp.Error = proto.String(fmt.Sprintf("unknown parameter %q", param))
+			return resp
+		}
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto, len(req.GetProtoFile()))
+	for _, f := range req.GetProtoFile() {
+		files[f.GetName()] = f
+	}
+	// Conflicting definitions of the same code are rejected across files too,
+	// matching protoc-gen-connect-errors-go
+	var all errordef.Set
+	for _, name := range req.GetFileToGenerate() {
+		f, ok := files[name]
+		if !ok {
+			resp.Error = proto.String(fmt.Sprintf("%s: file not found in request", name))
+			return resp
+		}
+		content, err := generateFile(f, &all)
+		if err != nil {
+			resp.Error = proto.String(err.Error())
+			return resp
+		}
+		if content == "" {
+			continue
+		}
+		resp.File = append(resp.File, &pluginpb.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(name, ".proto") + "_connect_errors.ts"),
+			Content: proto.String(content),
+		})
+	}
+	return resp
+}
+
+// ignoredParams are the standard protoc option and the protoc-gen-es options
+// commonly shared by all TypeScript plugins of a buf.gen.yaml. None of them
+// changes the generated file, which only imports @connectrpc/connect.
+var ignoredParams = map[string]bool{
+	"paths":            true,
+	"target":           true,
+	"import_extension": true,
+	"js_import_style":  true,
+	"keep_empty_files": true,
+	"ts_nocheck":       true,
+}
+
+// paramType describes how a placeholder type maps to TypeScript code.
+// parse is the name of the generated helper that decodes the metadata string.
+type paramType struct {
+	tsType string
+	parse  string
+}
+
+// paramTypes maps placeholder types to their generated TypeScript representation.
+// Parsers mirror the Go ParseXxx helpers and return undefined on invalid input.
+var paramTypes = map[string]paramType{
+	"":          {tsType: "string"},
+	"string":    {tsType: "string"},
+	"int":       {tsType: "bigint", parse: "parseIntParam"},
+	"decimal":   {tsType: "string", parse: "parseDecimalParam"},
+	"bool":      {tsType: "boolean", parse: "parseBoolParam"},
+	"timestamp": {tsType: "Date", parse: "parseTimestampParam"},
+	"duration":  {tsType: "number", parse: "parseDurationParam"},
+}
+
+// parseHelpers holds the TypeScript source of each parse helper.
+var parseHelpers = map[string]string{
+	// Ints are int64 in Go, so they are decoded to bigint without losing
+	// precision above 2^53; values outside the int64 range are rejected.
+	"parseIntParam": `function parseIntParam(s: string | undefined): bigint | undefined {
+  if (s === undefined || !/^[+-]?\d+$/.test(s)) {
+    return undefined;
+  }
+  const v = BigInt(s);
+  return BigInt.asIntN(64, v) === v ? v : undefined;
+}`,
+	// Decimals stay strings so that their scale survives, e.g. "12.50".
+	"parseDecimalParam": `function parseDecimalParam(s: string | undefined): string | undefined {
+  return s !== undefined && /^[+-]?\d+(\.\d+)?$/.test(s) ? s : undefined;
+}`,
+	"parseBoolParam": `function parseBoolParam(s: string | undefined): boolean | undefined {
+  switch (s) {
+    case "1":
+    case "t":
+    case "T":
+    case "true":
+    case "TRUE":
+    case "True":
+      return true;
+    case "0":
+    case "f":
+    case "F":
+    case "false":
+    case "FALSE":
+    case "False":
+      return false;
+    default:
+      return undefined;
+  }
+}`,
+	"parseTimestampParam": `function parseTimestampParam(s: string | undefined): Date | undefined {
+  const d = new Date(s ?? "");
+  return Number.isNaN(d.getTime()) ? undefined : d;
+}`,
+	// Go duration strings such as "1h30m" or "250ms" are decoded to milliseconds.
+	"parseDurationParam": `function parseDurationParam(s: string | undefined): number | undefined {
+  if (s === undefined || !/^[+-]?((\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h))+$/.test(s)) {
+    return undefined;
+  }
+  let ms = 0;
+  for (const match of s.matchAll(/(\d+(?:\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h)/g)) {
+    ms += Number(match[1]) * durationUnit(match[2]);
+  }
+  return s.startsWith("-") ? -ms : ms;
+}
+
+function durationUnit(unit: string | undefined): number {
+  switch (unit) {
+    case "h":
+      return 3.6e6;
+    case "m":
+      return 6e4;
+    case "s":
+      return 1e3;
+    case "ms":
+      return 1;
+    case "us":
+    case "µs":
+      return 1e-3;
+    default:
+      return 1e-6;
+  }
+}`,
+}
+
+// generateFile returns the TypeScript source for f, or "" if f declares no errors.
+// Definitions are added to all, which rejects conflicts with earlier files.
+func generateFile(f *descriptorpb.FileDescriptorProto, all *errordef.Set) (string, error) {
+	errors, fileDomain, err := errordef.FileErrors(f.GetOptions())
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", f.GetName(), err)
+	}
+	sources := make([]string, len(errors))
+	for i := range errors {
+		sources[i] = f.GetName() + " (file option)"
+	}
+	for _, svc := range f.GetService() {
+		for _, m := range svc.GetMethod() {
+			rpc := fmt.Sprintf("%s.%s.%s", f.GetPackage(), svc.GetName(), m.GetName())
+			defs, err := errordef.MethodErrors(m.GetOptions())
+			if err != nil {
+				return "", fmt.Errorf("%s: rpc %s: %w", f.GetName(), rpc, err)
+			}
+			errors = append(errors, defs...)
+			for range defs {
+				sources = append(sources, fmt.Sprintf("%s (rpc %s)", f.GetName(), rpc))
+			}
+		}
+	}
+	if len(errors) == 0 {
+		return "", nil
+	}
+
+	// Reject unknown placeholder types, apply the file-level default domain
+	// and deduplicate errors by code, failing on conflicting definitions
+	var unique errordef.Set
+	for i, e := range errors {
+		for _, p := range errordef.Params(e.Message) {
+			if _, ok := paramTypes[p.Type]; !ok {
+				return "", fmt.Errorf("%s: error %s: unknown placeholder type %q for {{%s}}", f.GetName(), e.Code, p.Type, p.Name)
+			}
+		}
+		if e.Domain == "" {
+			e.Domain = fileDomain
+		}
+		if err := unique.Add(e, sources[i]); err != nil {
+			return "", err
+		}
+	}
+	errors = unique.Defs()
+	for _, e := range errors {
+		if err := all.Add(e, f.GetName()); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	p := func(args ...any) {
+		fmt.Fprint(&b, args...)
+		b.WriteByte('\n')
+	}
+
+	p("// Code generated by protoc-gen-connect-errors-ts. DO NOT EDIT.")
+	p("// source: ", f.GetName())
+	p()
+	p(`import { ConnectError } from "@connectrpc/connect";`)
+	p()
+
+	// Generate error code constants
+	p("// Error code constants, identical to the Go ErrorCode constants.")
+	for _, e := range errors {
+		for _, l := range jsDoc(e) {
+			p(l)
+		}
+		p(fmt.Sprintf("export const Err%s = %q;", errorCodeToConstant(e.Code), e.Code))
+	}
+	p()
+
+	// Generate params interfaces for errors with template fields
+	for _, e := range errors {
+		params := clientParams(e)
+		if len(params) == 0 {
+			continue
+		}
+		baseName := errorCodeToConstant(e.Code)
+		p(fmt.Sprintf("/** %sParams holds the template parameters of %s. */", baseName, e.Code))
+		p(fmt.Sprintf("export interface %sParams {", baseName))
+		for _, param := range params {
+			if param.Type == "duration" {
+				p("  /** Duration in milliseconds. */")
+			}
+			p(fmt.Sprintf("  %s: %s;", fieldToCamelCase(param.Name), paramTypes[param.Type].tsType))
+		}
+		p("}")
+		p()
+	}
+
+	// Generate isXxx matchers
+	for _, e := range errors {
+		baseName := errorCodeToConstant(e.Code)
+		p(fmt.Sprintf("/** Reports whether err is a %s error. */", e.Code))
+		p(fmt.Sprintf("export function is%s(err: unknown): err is ConnectError {", baseName))
+		// With a domain, match on the (domain, reason) pair of the ErrorInfo detail
+		p(fmt.Sprintf("  return matchError(err, %q, Err%s);", e.Domain, baseName))
+		p("}")
+		p()
+	}
+
+	// Generate asXxx decoders for errors with template fields, collecting the
+	// support code they use; the runtime emits nothing the file doesn't call
+	decoders := false
+	helpers := make(map[string]bool)
+	for _, e := range errors {
+		params := clientParams(e)
+		if len(params) == 0 {
+			continue
+		}
+		decoders = true
+		baseName := errorCodeToConstant(e.Code)
+		p(fmt.Sprintf("/** Returns the template parameters of err if it is a %s error. */", e.Code))
+		p(fmt.Sprintf("export function as%s(err: unknown): %sParams | undefined {", baseName, baseName))
+		p(fmt.Sprintf("  if (!is%s(err)) {", baseName))
+		p("    return undefined;")
+		p("  }")
+		p("  const data = errorData(err);")
+		// Typed fields are parsed into locals first; a malformed value makes
+		// the decoder return undefined.
+		var parsed []string
+		for _, param := range params {
+			if parse := paramTypes[param.Type].parse; parse != "" {
+				helpers[parse] = true
+				local := localName(param.Name)
+				p(fmt.Sprintf("  const %s = %s(data[%q]);", local, parse, param.Name))
+				parsed = append(parsed, local+" === undefined")
+			}
+		}
+		if len(parsed) > 0 {
+			p(fmt.Sprintf("  if (%s) {", strings.Join(parsed, " || ")))
+			p("    return undefined;")
+			p("  }")
+		}
+		p("  return {")
+		for _, param := range params {
+			field := fieldToCamelCase(param.Name)
+			if paramTypes[param.Type].parse != "" {
+				p(fmt.Sprintf("    %s: %s,", field, localName(param.Name)))
+				continue
+			}
+			p(fmt.Sprintf("    %s: data[%q] ?? \"\",", field, param.Name))
+		}
+		p("  };")
+		p("}")
+		p()
+	}
+
+	p(runtime)
+	if decoders {
+		p()
+		p(errorDataRuntime)
+	}
+	names := make([]string, 0, len(helpers))
+	for name := range helpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p()
+		p(parseHelpers[name])
+	}
+	return b.String(), nil
+}
+
+// errorDataRuntime returns the ErrorInfo metadata of an error. It is only
+// emitted for files with asXxx decoders.
+const errorDataRuntime = `function errorData(err: ConnectError): Record<string, string> {
+  return findErrorInfo(err)?.metadata ?? {};
+}`
+
+// runtime is the file-local TypeScript support code shared by the generated
+// matchers and decoders. It decodes google.rpc.ErrorInfo without depending on
+// generated googleapis types.
+const runtime = `const errorCodeHeader = "x-error-code";
+
+interface ErrorInfo {
+  reason: string;
+  domain: string;
+  metadata: Record<string, string>;
+}
+
+function matchError(err: unknown, domain: string, code: string): err is ConnectError {
+  if (!(err instanceof ConnectError)) {
+    return false;
+  }
+  const info = findErrorInfo(err);
+  if (domain !== "") {
+    return info !== undefined && info.reason === code && info.domain === domain;
+  }
+  return err.metadata.get(errorCodeHeader) === code || info?.reason === code;
+}
+
+function findErrorInfo(err: ConnectError): ErrorInfo | undefined {
+  for (const detail of err.details) {
+    if ("type" in detail && detail.type === "google.rpc.ErrorInfo" && detail.value instanceof Uint8Array) {
+      return decodeErrorInfo(detail.value);
+    }
+  }
+  return undefined;
+}
+
+function decodeErrorInfo(buf: Uint8Array): ErrorInfo | undefined {
+  const info: ErrorInfo = { reason: "", domain: "", metadata: {} };
+  const text = new TextDecoder();
+  let pos = 0;
+  const varint = (): number => {
+    let value = 0;
+    for (let shift = 0; pos < buf.length; shift += 7) {
+      const byte = buf[pos++] ?? 0;
+      value += (byte & 0x7f) * 2 ** shift;
+      if (byte < 0x80) {
+        return value;
+      }
+    }
+    throw new RangeError("truncated varint");
+  };
+  const bytes = (): Uint8Array => {
+    const n = varint();
+    const end = pos + n;
+    if (end > buf.length) {
+      throw new RangeError("truncated field");
+    }
+    const b = buf.subarray(pos, end);
+    pos = end;
+    return b;
+  };
+  try {
+    while (pos < buf.length) {
+      const tag = varint();
+      const field = Math.floor(tag / 8);
+      switch (tag % 8) {
+        case 0:
+          varint();
+          break;
+        case 1:
+          pos += 8;
+          break;
+        case 5:
+          pos += 4;
+          break;
+        case 2: {
+          const b = bytes();
+          if (field === 1) {
+            info.reason = text.decode(b);
+          } else if (field === 2) {
+            info.domain = text.decode(b);
+          } else if (field === 3) {
+            // Map entries use fields 1 (key) and 2 (value), like reason and domain
+            const entry = decodeErrorInfo(b);
+            if (entry !== undefined) {
+              info.metadata[entry.reason] = entry.domain;
+            }
+          }
+          break;
+        }
+        default:
+          return undefined;
+      }
+    }
+  } catch {
+    return undefined;
+  }
+  return info;
+}`
+
+// clientParams returns the template parameters of e that reach clients. Sensitive
+// fields are left out, since servers never send their values.
+func clientParams(e errordef.Def) []errordef.Param {
+	var params []errordef.Param
+	for _, p := range errordef.Params(e.Message) {
+		if !slices.Contains(e.SensitiveFields, p.Name) {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// jsDoc returns the JSDoc block for the constant of e, carrying its
+// description and a @deprecated tag. It returns nil if there is nothing to document.
+func jsDoc(e errordef.Def) []string {
+	var body []string
+	if e.Description != "" {
+		for _, l := range strings.Split(strings.TrimSpace(e.Description), "\n") {
+			body = append(body, strings.TrimRight(" * "+strings.ReplaceAll(strings.TrimSpace(l), "*/", "*\\/"), " "))
+		}
+	}
+	if e.Deprecated {
+		if len(body) > 0 {
+			body = append(body, " *")
+		}
+		if e.ReplacedBy != "" {
+			body = append(body, fmt.Sprintf(" * @deprecated Use Err%s instead.", errorCodeToConstant(e.ReplacedBy)))
+		} else {
+			body = append(body, " * @deprecated")
+		}
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return append(append([]string{"/**"}, body...), " */")
+}
+
+// errorCodeToConstant converts an error code to a PascalCase name,
+// e.g. "ERROR_USER_NOT_FOUND" → "UserNotFound".
+func errorCodeToConstant(code string) string {
+	name := strings.TrimPrefix(code, "ERROR_")
+	var result string
+	for _, p := range strings.Split(name, "_") {
+		if len(p) > 0 {
+			result += strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		}
+	}
+	return result
+}
+
+// localName returns the name of the local variable holding the parsed value of
+// field in a generated decoder. The suffix keeps it clear of data, err and
+// reserved words, e.g. "default" → "defaultParam".
+func localName(field string) string {
+	return fieldToCamelCase(field) + "Param"
+}
+
+// fieldToCamelCase converts a snake_case template field to a camelCase
+// TypeScript property name, e.g. "product_id" → "productId".
+func fieldToCamelCase(field string) string {
+	var result string
+	for _, p := range strings.Split(field, "_") {
+		if len(p) == 0 {
+			continue
+		}
+		if result == "" {
+			result = strings.ToLower(p)
+			continue
+		}
+		result += strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+	}
+	return result
+}
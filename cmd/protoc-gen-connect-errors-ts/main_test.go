@@ -0,0 +1,259 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// errorDefBytes encodes a connecterrors.v1.ErrorDef in wire format.
+func errorDefBytes(code, message string, connectCode int) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, code)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, message)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(connectCode))
+	return b
+}
+
+// requestForTest returns a request for user/v1/user.proto declaring the given
+// file-level error definitions and file domain.
+func requestForTest(domain string, defs ...[]byte) *pluginpb.CodeGeneratorRequest {
+	var raw []byte
+	for _, d := range defs {
+		raw = protowire.AppendTag(raw, 50002, protowire.BytesType)
+		raw = protowire.AppendBytes(raw, d)
+	}
+	if domain != "" {
+		raw = protowire.AppendTag(raw, 50003, protowire.BytesType)
+		raw = protowire.AppendString(raw, domain)
+	}
+	opts := &descriptorpb.FileOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"user/v1/user.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user/v1/user.proto"),
+			Package: proto.String("user.v1"),
+			Syntax:  proto.String("proto3"),
+			Options: opts,
+		}},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	resp := generate(requestForTest("",
+		errorDefBytes("ERROR_USER_NOT_FOUND", "User '{{user_id}}' not found", 5),
+		errorDefBytes("ERROR_INTERNAL", "Internal error", 13),
+	))
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "user/v1/user_connect_errors.ts" {
+		t.Fatalf("unexpected files: %v", resp.File)
+	}
+	content := resp.File[0].GetContent()
+
+	for _, want := range []string{
+		`import { ConnectError } from "@connectrpc/connect";`,
+		`export const ErrUserNotFound = "ERROR_USER_NOT_FOUND";`,
+		"export interface UserNotFoundParams {\n  userId: string;\n}",
+		"export function isUserNotFound(err: unknown): err is ConnectError {",
+		`  return matchError(err, "", ErrUserNotFound);`,
+		"export function asUserNotFound(err: unknown): UserNotFoundParams | undefined {",
+		`    userId: data["user_id"] ?? "",`,
+		`const errorCodeHeader = "x-error-code";`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code missing %q\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "asInternal") {
+		t.Error("decoder should not be generated for errors without template fields")
+	}
+	if strings.Contains(content, "function parse") {
+		t.Error("parse helpers should only be emitted when used")
+	}
+	if strings.Contains(content, "Param === undefined") {
+		t.Error("decoders without typed fields should not check parse results")
+	}
+}
+
+func TestGenerateDomainAndTypedParams(t *testing.T) {
+	resp := generate(requestForTest("payments.acme.com",
+		errorDefBytes("ERROR_LIMIT", "{{amount:decimal}} over {{limit:int}} until {{reset_at:timestamp}}, retry in {{wait:duration}}", 9),
+	))
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+
+	for _, want := range []string{
+		`  return matchError(err, "payments.acme.com", ErrLimit);`,
+		"  amount: string;",
+		"  limit: bigint;",
+		"  resetAt: Date;",
+		"  /** Duration in milliseconds. */\n  wait: number;",
+		`  const amountParam = parseDecimalParam(data["amount"]);`,
+		`  const resetAtParam = parseTimestampParam(data["reset_at"]);`,
+		"  if (amountParam === undefined || limitParam === undefined || resetAtParam === undefined || waitParam === undefined) {\n    return undefined;\n  }",
+		"    amount: amountParam,",
+		"    resetAt: resetAtParam,",
+		"  return s !== undefined && /^[+-]?\\d+(\\.\\d+)?$/.test(s) ? s : undefined;",
+		"function parseDecimalParam(",
+		"function parseDurationParam(",
+		"function parseIntParam(s: string | undefined): bigint | undefined {",
+		"  return BigInt.asIntN(64, v) === v ? v : undefined;",
+		"function parseTimestampParam(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code missing %q\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "function parseBoolParam(") {
+		t.Error("unused parseBoolParam helper emitted")
+	}
+}
+
+func TestGenerateMethodErrors(t *testing.T) {
+	def := errorDefBytes("ERROR_USER_NOT_FOUND", "User not found", 5)
+	raw := protowire.AppendTag(nil, 50001, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, def)
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+
+	req := requestForTest("")
+	req.ProtoFile[0].Service = []*descriptorpb.ServiceDescriptorProto{{
+		Name: proto.String("UserService"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			{Name: proto.String("GetUser"), Options: opts},
+			{Name: proto.String("DeleteUser"), Options: opts},
+		},
+	}}
+
+	resp := generate(req)
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	if n := strings.Count(resp.File[0].GetContent(), "export const ErrUserNotFound"); n != 1 {
+		t.Errorf("ErrUserNotFound declared %d times, want 1", n)
+	}
+	if strings.Contains(resp.File[0].GetContent(), "function errorData(") {
+		t.Error("errorData should only be emitted when a decoder uses it")
+	}
+}
+
+func TestGenerateNoErrors(t *testing.T) {
+	resp := generate(requestForTest(""))
+	if resp.Error != nil || len(resp.File) != 0 {
+		t.Errorf("expected no output, got files=%v error=%q", resp.File, resp.GetError())
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	resp := generate(requestForTest("", errorDefBytes("ERROR_X", "{{n:money}}", 13)))
+	if !strings.Contains(resp.GetError(), `unknown placeholder type "money"`) {
+		t.Errorf("error = %q, want unknown placeholder type", resp.GetError())
+	}
+
+	req := requestForTest("")
+	req.Parameter = proto.String("paths=source_relative,lang=go")
+	if resp := generate(req); !strings.Contains(resp.GetError(), `unknown parameter "lang=go"`) {
+		t.Errorf("error = %q, want unknown parameter", resp.GetError())
+	}
+}
+
+func TestGenerateStandardParameters(t *testing.T) {
+	req := requestForTest("", errorDefBytes("ERROR_X", "X", 13))
+	req.Parameter = proto.String("paths=source_relative,target=ts,import_extension=js")
+	resp := generate(req)
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "user/v1/user_connect_errors.ts" {
+		t.Errorf("unexpected files: %v", resp.File)
+	}
+}
+
+func TestFieldToCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"id":           "id",
+		"product_id":   "productId",
+		"RESET_AT":     "resetAt",
+		"a__b":         "aB",
+		"order_line_n": "orderLineN",
+	}
+	for in, want := range tests {
+		if got := fieldToCamelCase(in); got != want {
+			t.Errorf("fieldToCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateConflictingDefinitions(t *testing.T) {
+	resp := generate(requestForTest("",
+		errorDefBytes("ERROR_X", "X", 5),
+		errorDefBytes("ERROR_X", "X", 13),
+	))
+	if !strings.Contains(resp.GetError(), "conflicting definitions of ERROR_X") {
+		t.Errorf("error = %q, want conflict", resp.GetError())
+	}
+}
+
+func TestGenerateDocsAndDeprecation(t *testing.T) {
+	gone := errorDefBytes("ERROR_USER_GONE", "user gone", 5)
+	gone = protowire.AppendTag(gone, 7, protowire.BytesType)
+	gone = protowire.AppendString(gone, "Returned when the user was deleted.")
+	gone = protowire.AppendTag(gone, 10, protowire.VarintType)
+	gone = protowire.AppendVarint(gone, 1)
+	gone = protowire.AppendTag(gone, 11, protowire.BytesType)
+	gone = protowire.AppendString(gone, "ERROR_USER_NOT_FOUND")
+
+	resp := generate(requestForTest("", gone, errorDefBytes("ERROR_USER_NOT_FOUND", "user not found", 5)))
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+
+	want := "/**\n * Returned when the user was deleted.\n *\n * @deprecated Use ErrUserNotFound instead.\n */\nexport const ErrUserGone = \"ERROR_USER_GONE\";\nexport const ErrUserNotFound"
+	if !strings.Contains(content, want) {
+		t.Errorf("generated code missing %q\n%s", want, content)
+	}
+}
+
+func TestGenerateSensitiveFields(t *testing.T) {
+	def := errorDefBytes("ERROR_INVALID_CREDENTIALS", "Invalid credentials for '{{email}}' in {{tenant}}", 16)
+	def = protowire.AppendTag(def, 13, protowire.BytesType)
+	def = protowire.AppendString(def, "email")
+	secret := errorDefBytes("ERROR_TOKEN_REJECTED", "Token {{token}} rejected after {{attempts:int}} attempts", 16)
+	secret = protowire.AppendTag(secret, 13, protowire.BytesType)
+	secret = protowire.AppendString(secret, "token")
+	secret = protowire.AppendTag(secret, 13, protowire.BytesType)
+	secret = protowire.AppendString(secret, "attempts")
+
+	resp := generate(requestForTest("", def, secret))
+	if resp.Error != nil {
+		t.Fatalf("generate failed: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+
+	if !strings.Contains(content, "export interface InvalidCredentialsParams {\n  tenant: string;\n}") {
+		t.Errorf("expected sensitive email to be left out of the params\n%s", content)
+	}
+	if strings.Contains(content, "TokenRejectedParams") || strings.Contains(content, "asTokenRejected") {
+		t.Error("expected no params or decoder when every field is sensitive")
+	}
+	if strings.Contains(content, "function parseIntParam(") {
+		t.Error("parse helpers of sensitive fields should not be emitted")
+	}
+	if !strings.Contains(content, "function errorData(") {
+		t.Error("expected errorData for the asInvalidCredentials decoder")
+	}
+}
@@ -0,0 +1,135 @@
+package connecterrors
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ConflictMode controls how Register and RegisterAll handle a definition that
+// differs from one already registered under the same code. Re-registering an
+// identical definition or overriding a built-in default is never a conflict.
+type ConflictMode int
+
+const (
+	// ConflictOverwrite replaces the existing definition. This is the default.
+	ConflictOverwrite ConflictMode = iota
+
+	// ConflictReport invokes the ConflictFunc and replaces the existing definition.
+	ConflictReport
+
+	// ConflictPanic panics and leaves the Registry unchanged.
+	ConflictPanic
+)
+
+// Conflict describes a registration that replaced a different definition of the same code.
+type Conflict struct {
+	Existing Error
+	Incoming Error
+}
+
+// String implements fmt.Stringer.
+func (c Conflict) String() string {
+	return fmt.Sprintf("conflicting registration of %s: %+v replaces %+v", c.Existing.Code, c.Incoming, c.Existing)
+}
+
+// ConflictFunc is invoked for each conflicting registration in ConflictReport mode.
+type ConflictFunc func(c Conflict)
+
+// SetConflictMode sets how r handles conflicting registrations. fn may be nil.
+//
+// Generated packages register their errors in init, before main can opt in,
+// so conflicts are always recorded: switching to ConflictReport reports the
+// conflicts recorded so far and switching to ConflictPanic panics if there are any.
+//
+// Example:
+//
+//	func main() {
+//	    cerr.SetConflictMode(cerr.ConflictPanic, nil)
+//	    ...
+//	}
+func (r *Registry) SetConflictMode(mode ConflictMode, fn ConflictFunc) {
+	r.writeMu.Lock()
+	r.conflictMode = mode
+	r.onConflict = fn
+	recorded := append([]Conflict(nil), r.conflicts...)
+	r.writeMu.Unlock()
+
+	switch mode {
+	case ConflictPanic:
+		if len(recorded) > 0 {
+			panic("connecterrors: " + recorded[0].String())
+		}
+	case ConflictReport:
+		if fn != nil {
+			for _, c := range recorded {
+				fn(c)
+			}
+		}
+	}
+}
+
+// Conflicts returns the conflicting registrations recorded by r, in order.
+func (r *Registry) Conflicts() []Conflict {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	return append([]Conflict(nil), r.conflicts...)
+}
+
+// SetConflictMode sets how the default Registry handles conflicting registrations.
+func SetConflictMode(mode ConflictMode, fn ConflictFunc) { defaultRegistry.SetConflictMode(mode, fn) }
+
+// register adds errs under writeMu, detecting conflicts with the current
+// definitions and earlier entries of errs. The ConflictFunc is invoked after
+// the lock is released so it may safely use r.
+func (r *Registry) register(errs []Error) {
+	r.writeMu.Lock()
+	current := r.load()
+	updated := make(map[ErrorCode]Error, len(current)+len(errs))
+	for k, v := range current {
+		updated[k] = v
+	}
+	var found []Conflict
+	for _, err := range errs {
+		err.SensitiveFields = slices.Clone(err.SensitiveFields)
+		if existing, ok := updated[err.Code]; ok && conflicts(existing, err) {
+			c := Conflict{Existing: existing, Incoming: err}
+			if r.conflictMode == ConflictPanic {
+				r.writeMu.Unlock()
+				panic("connecterrors: " + c.String())
+			}
+			found = append(found, c)
+		}
+		updated[err.Code] = err
+	}
+	r.errs.Store(updated)
+	r.conflicts = append(r.conflicts, found...)
+	mode, fn := r.conflictMode, r.onConflict
+	r.writeMu.Unlock()
+
+	if mode == ConflictReport && fn != nil {
+		for _, c := range found {
+			fn(c)
+		}
+	}
+}
+
+// conflicts reports whether registering incoming over existing is a conflict.
+// Built-in defaults are meant to be overridden and are never in conflict.
+func conflicts(existing, incoming Error) bool {
+	if sameError(existing, incoming) {
+		return false
+	}
+	def, builtin := defaultErrors[existing.Code]
+	return !builtin || !sameError(def, existing)
+}
+
+// sameError reports whether a and b are identical definitions. A nil and an
+// empty SensitiveFields are considered equal.
+func sameError(a, b Error) bool {
+	if !slices.Equal(a.SensitiveFields, b.SensitiveFields) {
+		return false
+	}
+	a.SensitiveFields, b.SensitiveFields = nil, nil
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,127 @@
+package connecterrors_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+var (
+	conflictA = connecterrors.Error{Code: "ERROR_DUP", MessageTpl: "A", ConnectCode: connect.CodeNotFound}
+	conflictB = connecterrors.Error{Code: "ERROR_DUP", MessageTpl: "B", ConnectCode: connect.CodeNotFound}
+)
+
+func TestConflictOverwriteDefault(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	r.Register(conflictA)
+	r.Register(conflictB)
+
+	if e := r.MustLookup("ERROR_DUP"); e.MessageTpl != "B" {
+		t.Errorf("MessageTpl = %q, want B (last registration wins)", e.MessageTpl)
+	}
+	if got := r.Conflicts(); len(got) != 1 || !reflect.DeepEqual(got[0].Existing, conflictA) || !reflect.DeepEqual(got[0].Incoming, conflictB) {
+		t.Errorf("Conflicts() = %v, want one A→B conflict", got)
+	}
+}
+
+func TestConflictReport(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	var reported []connecterrors.Conflict
+	r.SetConflictMode(connecterrors.ConflictReport, func(c connecterrors.Conflict) {
+		reported = append(reported, c)
+	})
+
+	r.Register(conflictA)
+	r.Register(conflictA) // identical, not a conflict
+	r.RegisterAll([]connecterrors.Error{conflictB})
+
+	if len(reported) != 1 || !reflect.DeepEqual(reported[0].Incoming, conflictB) {
+		t.Fatalf("reported = %v, want one conflict", reported)
+	}
+	if e := r.MustLookup("ERROR_DUP"); e.MessageTpl != "B" {
+		t.Errorf("MessageTpl = %q, want B", e.MessageTpl)
+	}
+}
+
+func TestConflictSensitiveFields(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	sensitive := conflictA
+	sensitive.SensitiveFields = []string{"email"}
+	r.Register(sensitive)
+	r.Register(connecterrors.Error{Code: "ERROR_DUP", MessageTpl: "A", ConnectCode: connect.CodeNotFound, SensitiveFields: []string{"email"}})
+	if got := r.Conflicts(); len(got) != 0 {
+		t.Fatalf("identical sensitive fields reported as conflicts: %v", got)
+	}
+
+	r.Register(conflictA)
+	if got := r.Conflicts(); len(got) != 1 {
+		t.Errorf("Conflicts() = %v, want dropping the sensitive fields to conflict", got)
+	}
+}
+
+func TestConflictReportRecorded(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	r.Register(conflictA)
+	r.Register(conflictB) // e.g. during init, before opting in
+
+	var reported int
+	r.SetConflictMode(connecterrors.ConflictReport, func(connecterrors.Conflict) { reported++ })
+	if reported != 1 {
+		t.Errorf("reported %d recorded conflicts, want 1", reported)
+	}
+}
+
+func TestConflictPanic(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	r.SetConflictMode(connecterrors.ConflictPanic, nil)
+	r.Register(conflictA)
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Fatal("expected panic on conflicting registration")
+		}
+		if !strings.Contains(rec.(string), "ERROR_DUP") {
+			t.Errorf("panic = %v, want error code", rec)
+		}
+		if e := r.MustLookup("ERROR_DUP"); e.MessageTpl != "A" {
+			t.Errorf("Registry changed after panic: MessageTpl = %q", e.MessageTpl)
+		}
+	}()
+	r.RegisterAll([]connecterrors.Error{
+		{Code: "ERROR_OTHER", MessageTpl: "Other"},
+		conflictB,
+	})
+}
+
+func TestConflictPanicRecorded(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	r.Register(conflictA)
+	r.Register(conflictB)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for previously recorded conflict")
+		}
+	}()
+	r.SetConflictMode(connecterrors.ConflictPanic, nil)
+}
+
+func TestConflictBuiltinOverride(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	r.SetConflictMode(connecterrors.ConflictPanic, nil)
+
+	// Overriding a built-in default is intended and not a conflict
+	r.Register(connecterrors.Error{
+		Code:        connecterrors.ErrNotFound,
+		MessageTpl:  "Nothing here",
+		ConnectCode: connect.CodeNotFound,
+	})
+	if len(r.Conflicts()) != 0 {
+		t.Errorf("Conflicts() = %v, want none", r.Conflicts())
+	}
+}
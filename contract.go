@@ -0,0 +1,153 @@
+package connecterrors
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+)
+
+// Contract maps fully-qualified procedure names (e.g. "/user.v1.UserService/GetUser")
+// to the error codes they declare. protoc-gen-connect-errors-go generates one
+// Contract per service from method-level and file-level error definitions and
+// registers it in init.
+type Contract map[string][]ErrorCode
+
+// RegisterContract adds or replaces the declared error codes of the procedures in c.
+// It is safe for concurrent use. Uses copy-on-write for lock-free reads.
+func (r *Registry) RegisterContract(c Contract) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	current := r.loadContracts()
+	updated := make(map[string]map[ErrorCode]struct{}, len(current)+len(c))
+	for k, v := range current {
+		updated[k] = v
+	}
+	for procedure, codes := range c {
+		set := make(map[ErrorCode]struct{}, len(codes))
+		for _, code := range codes {
+			set[code] = struct{}{}
+		}
+		updated[procedure] = set
+	}
+	r.contracts.Store(updated)
+}
+
+// Declares reports whether procedure declares code in its contract.
+// The second result is false if no contract is registered for procedure.
+func (r *Registry) Declares(procedure string, code ErrorCode) (declared bool, hasContract bool) {
+	set, ok := r.loadContracts()[procedure]
+	if !ok {
+		return false, false
+	}
+	_, declared = set[code]
+	return declared, true
+}
+
+// loadContracts returns the current immutable contract snapshot.
+func (r *Registry) loadContracts() map[string]map[ErrorCode]struct{} {
+	v := r.contracts.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(map[string]map[ErrorCode]struct{})
+}
+
+// RegisterContract adds procedure contracts to the default Registry.
+func RegisterContract(c Contract) { defaultRegistry.RegisterContract(c) }
+
+// ContractMode controls how ContractInterceptor handles undeclared errors.
+type ContractMode int
+
+const (
+	// ContractReport invokes the callback and returns the error unchanged.
+	ContractReport ContractMode = iota
+
+	// ContractStrict invokes the callback and replaces the error with
+	// ERROR_INTERNAL. The original error is kept as the cause for server-side logging.
+	ContractStrict
+)
+
+// ContractViolationFunc is invoked when a handler returns a domain error whose
+// code is not declared in the procedure's contract.
+type ContractViolationFunc func(ctx context.Context, procedure string, connectErr *connect.Error)
+
+// ContractInterceptor is a server-side Connect interceptor that enforces
+// per-procedure error contracts registered in the default Registry.
+// Only errors carrying a domain error code are checked, and procedures without
+// a registered contract are not checked. fn may be nil.
+//
+// Example:
+//
+//	interceptor := cerr.ContractInterceptor(cerr.ContractStrict,
+//	    func(ctx context.Context, procedure string, err *connect.Error) {
+//	        slog.WarnContext(ctx, "undeclared rpc error", "procedure", procedure, "error", err)
+//	    },
+//	)
+func ContractInterceptor(mode ContractMode, fn ContractViolationFunc) connect.Interceptor {
+	return defaultRegistry.ContractInterceptor(mode, fn)
+}
+
+// ContractInterceptor returns a Connect interceptor that enforces the contracts
+// registered in r. See the package-level ContractInterceptor.
+func (r *Registry) ContractInterceptor(mode ContractMode, fn ContractViolationFunc) connect.Interceptor {
+	return &contractInterceptor{reg: r, mode: mode, fn: fn}
+}
+
+// contractInterceptor implements connect.Interceptor for ContractInterceptor.
+type contractInterceptor struct {
+	reg  *Registry
+	mode ContractMode
+	fn   ContractViolationFunc
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *contractInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil && !req.Spec().IsClient {
+			err = i.check(ctx, req.Spec().Procedure, err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *contractInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *contractInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, conn)
+		if err != nil {
+			err = i.check(ctx, conn.Spec().Procedure, err)
+		}
+		return err
+	}
+}
+
+// check reports err if its domain code is not declared for procedure and,
+// in strict mode, replaces it with ERROR_INTERNAL.
+func (i *contractInterceptor) check(ctx context.Context, procedure string, err error) error {
+	var connectErr *connect.Error
+	if !asConnectError(err, &connectErr) {
+		return err
+	}
+	code, ok := ExtractErrorCode(connectErr)
+	if !ok {
+		return err
+	}
+	declared, hasContract := i.reg.Declares(procedure, ErrorCode(code))
+	if !hasContract || declared {
+		return err
+	}
+
+	if i.fn != nil {
+		i.fn(ctx, procedure, connectErr)
+	}
+	if i.mode == ContractStrict {
+		return i.reg.New(ErrInternal, nil, WithCause(err))
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package connecterrors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// fakeRequest is a minimal connect.AnyRequest carrying a Spec for tests.
+type fakeRequest struct {
+	connect.AnyRequest
+	spec connect.Spec
+}
+
+func (r *fakeRequest) Spec() connect.Spec { return r.spec }
+
+const getUserProcedure = "/user.v1.UserService/GetUser"
+
+func newContractRegistry() *connecterrors.Registry {
+	reg := connecterrors.NewRegistry()
+	reg.RegisterContract(connecterrors.Contract{
+		getUserProcedure: {connecterrors.ErrNotFound, connecterrors.ErrInvalidArgument},
+	})
+	return reg
+}
+
+func TestDeclares(t *testing.T) {
+	reg := newContractRegistry()
+
+	if declared, ok := reg.Declares(getUserProcedure, connecterrors.ErrNotFound); !ok || !declared {
+		t.Errorf("Declares(NotFound) = %v, %v; want true, true", declared, ok)
+	}
+	if declared, ok := reg.Declares(getUserProcedure, connecterrors.ErrAborted); !ok || declared {
+		t.Errorf("Declares(Aborted) = %v, %v; want false, true", declared, ok)
+	}
+	if _, ok := reg.Declares("/other.v1.Service/Method", connecterrors.ErrNotFound); ok {
+		t.Error("expected no contract for unknown procedure")
+	}
+}
+
+func TestContractInterceptorReport(t *testing.T) {
+	reg := newContractRegistry()
+	var violations []string
+	interceptor := reg.ContractInterceptor(connecterrors.ContractReport, func(_ context.Context, procedure string, _ *connect.Error) {
+		violations = append(violations, procedure)
+	})
+
+	undeclared := reg.New(connecterrors.ErrAborted, connecterrors.M{"reason": "conflict"})
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, undeclared
+	})
+
+	_, err := handler(context.Background(), &fakeRequest{spec: connect.Spec{Procedure: getUserProcedure}})
+	if !errors.Is(err, undeclared) {
+		t.Errorf("report mode should return the original error, got %v", err)
+	}
+	if len(violations) != 1 || violations[0] != getUserProcedure {
+		t.Errorf("violations = %v, want [%s]", violations, getUserProcedure)
+	}
+}
+
+func TestContractInterceptorStrict(t *testing.T) {
+	reg := newContractRegistry()
+	interceptor := reg.ContractInterceptor(connecterrors.ContractStrict, nil)
+
+	undeclared := reg.New(connecterrors.ErrAborted, connecterrors.M{"reason": "conflict"})
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, undeclared
+	})
+
+	_, err := handler(context.Background(), &fakeRequest{spec: connect.Spec{Procedure: getUserProcedure}})
+	if !errors.Is(err, connecterrors.ErrInternal) {
+		t.Errorf("strict mode should rewrite to ErrInternal, got %v", err)
+	}
+	if connect.CodeOf(err) != connect.CodeInternal {
+		t.Errorf("CodeOf = %v, want CodeInternal", connect.CodeOf(err))
+	}
+	if !errors.Is(err, undeclared) {
+		t.Error("original error should be kept as the cause")
+	}
+}
+
+func TestContractInterceptorDeclared(t *testing.T) {
+	reg := newContractRegistry()
+	called := false
+	interceptor := reg.ContractInterceptor(connecterrors.ContractStrict, func(context.Context, string, *connect.Error) {
+		called = true
+	})
+
+	declared := reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, declared
+	})
+
+	_, err := handler(context.Background(), &fakeRequest{spec: connect.Spec{Procedure: getUserProcedure}})
+	if !errors.Is(err, declared) || called {
+		t.Errorf("declared errors must pass through untouched, got %v (called=%v)", err, called)
+	}
+
+	// Procedures without a contract are not checked.
+	_, err = handler(context.Background(), &fakeRequest{spec: connect.Spec{Procedure: "/other.v1.Service/Method"}})
+	if !errors.Is(err, declared) || called {
+		t.Errorf("unchecked procedure: got %v (called=%v)", err, called)
+	}
+}
@@ -0,0 +1,127 @@
+package connecterrors
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// DebugFunc reports whether a request may receive google.rpc.DebugInfo
+// details. It is called with the handler context and the request headers.
+type DebugFunc func(ctx context.Context, header http.Header) bool
+
+// DebugHeader returns a DebugFunc that allows debug details only for requests
+// whose header key equals value. The comparison runs in constant time, so value
+// can be a shared secret. An empty value allows no request.
+//
+// Example:
+//
+//	cerr.DebugInfoInterceptor(cerr.DebugHeader("X-Debug-Token", os.Getenv("DEBUG_TOKEN")))
+func DebugHeader(key, value string) DebugFunc {
+	return func(_ context.Context, header http.Header) bool {
+		got := header.Get(key)
+		return value != "" && subtle.ConstantTimeCompare([]byte(got), []byte(value)) == 1
+	}
+}
+
+// AlwaysDebug is a DebugFunc that allows debug details for every request.
+// Only use it in development builds, e.g. behind a build tag.
+//
+// Example:
+//
+//	cerr.DebugInfoInterceptor(cerr.AlwaysDebug)
+func AlwaysDebug(context.Context, http.Header) bool { return true }
+
+// AddDebugInfo attaches a google.rpc.DebugInfo detail to a domain error. Its
+// detail is CodedError.InternalMessage and its stack entries are the messages of
+// the cause chain, or the stack of a recovered panic. It does nothing if
+// connectErr carries no *CodedError or already has a DebugInfo detail.
+// Returns the same error for method chaining.
+//
+// DebugInfo exposes internal error messages: only add it for trusted callers.
+func AddDebugInfo(connectErr *connect.Error) *connect.Error {
+	var coded *CodedError
+	if connectErr == nil || !errors.As(connectErr.Unwrap(), &coded) {
+		return connectErr
+	}
+	if _, ok := ExtractDebugInfo(connectErr); ok {
+		return connectErr
+	}
+	info := &errdetails.DebugInfo{
+		Detail:       coded.InternalMessage(),
+		StackEntries: causeChain(coded.cause),
+	}
+	var panicErr *PanicError
+	if errors.As(coded.cause, &panicErr) && len(panicErr.Stack) > 0 {
+		info.StackEntries = strings.Split(strings.TrimSpace(string(panicErr.Stack)), "\n")
+	}
+	if d, err := connect.NewErrorDetail(info); err == nil {
+		connectErr.AddDetail(d)
+	}
+	return connectErr
+}
+
+// DebugInfoInterceptor is a server-side Connect interceptor that attaches a
+// google.rpc.DebugInfo detail (see AddDebugInfo) to the domain errors returned
+// by unary and streaming handlers, if allow reports true for the request.
+// A nil allow never attaches it; pass AlwaysDebug to attach it to every error.
+//
+// Example:
+//
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(
+//	        cerr.DebugInfoInterceptor(cerr.DebugHeader("X-Debug-Token", debugToken)),
+//	    ),
+//	))
+func DebugInfoInterceptor(allow DebugFunc) connect.Interceptor {
+	return &debugInterceptor{allow: allow}
+}
+
+// debugInterceptor implements connect.Interceptor for DebugInfoInterceptor.
+type debugInterceptor struct {
+	allow DebugFunc
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *debugInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil && !req.Spec().IsClient {
+			i.debug(ctx, req.Header(), err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *debugInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *debugInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, conn)
+		if err != nil {
+			i.debug(ctx, conn.RequestHeader(), err)
+		}
+		return err
+	}
+}
+
+// debug adds a DebugInfo detail to err if the request is allowed to see it.
+func (i *debugInterceptor) debug(ctx context.Context, header http.Header, err error) {
+	var connectErr *connect.Error
+	if !asConnectError(err, &connectErr) {
+		return
+	}
+	if i.allow == nil || !i.allow(ctx, header) {
+		return
+	}
+	AddDebugInfo(connectErr)
+}
@@ -0,0 +1,119 @@
+package connecterrors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func TestWithInternalMessage(t *testing.T) {
+	err := connecterrors.Wrap(connecterrors.ErrUnavailable, errors.New("dial tcp: refused"), nil,
+		connecterrors.WithInternalMessage("inventory pool exhausted"),
+	)
+	if err.Message() != "Service temporarily unavailable" {
+		t.Errorf("Message() = %q", err.Message())
+	}
+	var coded *connecterrors.CodedError
+	if !errors.As(err, &coded) {
+		t.Fatal("expected *CodedError")
+	}
+	if got, want := coded.InternalMessage(), "inventory pool exhausted: dial tcp: refused"; got != want {
+		t.Errorf("InternalMessage() = %q, want %q", got, want)
+	}
+}
+
+// callDebug runs a unary handler failing with err through interceptor with
+// the given request header.
+func callDebug(t *testing.T, interceptor connect.Interceptor, err error, header http.Header) error {
+	t.Helper()
+	handler := interceptor.WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, err
+	})
+	req := connect.NewRequest(&emptypb.Empty{})
+	for k, v := range header {
+		req.Header()[k] = v
+	}
+	_, got := handler(context.Background(), req)
+	return got
+}
+
+func TestDebugInfoInterceptor(t *testing.T) {
+	interceptor := connecterrors.DebugInfoInterceptor(connecterrors.DebugHeader("X-Debug-Token", "s3cret"))
+	newErr := func() error {
+		return connecterrors.Wrap(connecterrors.ErrInternal, errors.New("pq: relation \"users\" does not exist"), nil)
+	}
+
+	err := callDebug(t, interceptor, newErr(), http.Header{"X-Debug-Token": {"s3cret"}})
+	info, ok := connecterrors.ExtractDebugInfo(err)
+	if !ok {
+		t.Fatal("expected DebugInfo for a trusted request")
+	}
+	if want := "Internal server error: pq: relation \"users\" does not exist"; info.GetDetail() != want {
+		t.Errorf("Detail = %q, want %q", info.GetDetail(), want)
+	}
+	if len(info.GetStackEntries()) != 1 {
+		t.Errorf("StackEntries = %v, want the cause", info.GetStackEntries())
+	}
+
+	for _, header := range []http.Header{nil, {"X-Debug-Token": {"guess"}}} {
+		if _, ok := connecterrors.ExtractDebugInfo(callDebug(t, interceptor, newErr(), header)); ok {
+			t.Errorf("unexpected DebugInfo for header %v", header)
+		}
+	}
+}
+
+func TestDebugInfoInterceptorStreaming(t *testing.T) {
+	handler := connecterrors.DebugInfoInterceptor(connecterrors.AlwaysDebug).WrapStreamingHandler(
+		func(context.Context, connect.StreamingHandlerConn) error {
+			return connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+		},
+	)
+	err := handler(context.Background(), &fakeHandlerConn{})
+	if info, ok := connecterrors.ExtractDebugInfo(err); !ok || info.GetDetail() != "Resource '1' not found" {
+		t.Errorf("DebugInfo = %v", info)
+	}
+}
+
+func TestDebugInfoInterceptorNil(t *testing.T) {
+	interceptor := connecterrors.DebugInfoInterceptor(nil)
+	err := callDebug(t, interceptor, connecterrors.Wrap(connecterrors.ErrInternal, errors.New("boom"), nil), http.Header{"X-Debug-Token": {"s3cret"}})
+	if _, ok := connecterrors.ExtractDebugInfo(err); ok {
+		t.Error("a nil DebugFunc must never attach DebugInfo")
+	}
+
+	handler := interceptor.WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	})
+	if _, ok := connecterrors.ExtractDebugInfo(handler(context.Background(), &fakeHandlerConn{})); ok {
+		t.Error("a nil DebugFunc must never attach DebugInfo to streaming errors")
+	}
+}
+
+func TestAddDebugInfoPanic(t *testing.T) {
+	handler := connecterrors.RecoverInterceptor(nil).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			panic("boom")
+		},
+	)
+	_, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("err = %v, want *connect.Error", err)
+	}
+	connecterrors.AddDebugInfo(connectErr)
+	connecterrors.AddDebugInfo(connectErr)
+
+	info, ok := connecterrors.ExtractDebugInfo(connectErr)
+	if !ok || len(info.GetStackEntries()) < 2 {
+		t.Fatalf("DebugInfo = %v, want the panic stack", info)
+	}
+	if len(connectErr.Details()) != 2 {
+		t.Errorf("len(Details) = %d, want ErrorInfo and one DebugInfo", len(connectErr.Details()))
+	}
+}
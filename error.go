@@ -3,7 +3,9 @@ package connecterrors
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"sync/atomic"
+	"time"
 
 	"connectrpc.com/connect"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -29,6 +31,14 @@ type ErrorCode string
 // This method satisfies the ErrorCoder interface.
 func (c ErrorCode) Code() string { return string(c) }
 
+// Error implements the error interface so that an ErrorCode can be used as an
+// errors.Is target. A *CodedError matches any ErrorCode with the same code.
+//
+// Example:
+//
+//	if errors.Is(err, cerr.ErrNotFound) { ... }
+func (c ErrorCode) Error() string { return string(c) }
+
 // M is a shorthand type for template data maps.
 // Keys are placeholder names, values are their replacements.
 //
@@ -39,8 +49,9 @@ type M map[string]string
 
 // headerKeys holds the configured metadata key names.
 type headerKeys struct {
-	errorCode string
-	retryable string
+	errorCode  string
+	retryable  string
+	retryAfter string
 }
 
 // headerKeysVal stores the current headerKeys atomically for lock-free reads.
@@ -48,8 +59,9 @@ var headerKeysVal atomic.Value
 
 func init() {
 	headerKeysVal.Store(headerKeys{
-		errorCode: "x-error-code",
-		retryable: "x-retryable",
+		errorCode:  "x-error-code",
+		retryable:  "x-retryable",
+		retryAfter: "retry-after",
 	})
 }
 
@@ -75,9 +87,24 @@ func SetHeaderKeys(errorCodeKey, retryableKey string) {
 	headerKeysVal.Store(current)
 }
 
+// SetRetryAfterHeaderKey reconfigures the metadata key used to mirror the retry
+// delay of retryable errors (default "retry-after"). This is safe for concurrent use.
+//
+// Example:
+//
+//	cerr.SetRetryAfterHeaderKey("x-retry-after")
+func SetRetryAfterHeaderKey(key string) {
+	current := getHeaderKeys()
+	if key != "" {
+		current.retryAfter = key
+	}
+	headerKeysVal.Store(current)
+}
+
 // setMeta attaches error code and retryable metadata to a Connect error.
-// It also attaches google.rpc.ErrorInfo and google.rpc.RetryInfo protobuf details.
-func setMeta(connectErr *connect.Error, e Error, data M) {
+// It also attaches google.rpc.ErrorInfo, google.rpc.RetryInfo and google.rpc.Help
+// protobuf details. Sensitive fields of e are left out of the ErrorInfo metadata.
+func (r *Registry) setMeta(connectErr *connect.Error, e Error, data M) {
 	hk := getHeaderKeys()
 	connectErr.Meta().Set(hk.errorCode, string(e.Code))
 	if e.Retryable {
@@ -88,9 +115,9 @@ func setMeta(connectErr *connect.Error, e Error, data M) {
 
 	info := &errdetails.ErrorInfo{
 		Reason: string(e.Code),
-		Domain: "connecterrors",
+		Domain: r.domainFor(e),
 	}
-	if len(data) > 0 {
+	if data = omitSensitive(data, e.SensitiveFields); len(data) > 0 {
 		info.Metadata = make(map[string]string, len(data))
 		for k, v := range data {
 			info.Metadata[k] = v
@@ -102,29 +129,42 @@ func setMeta(connectErr *connect.Error, e Error, data M) {
 
 	if e.Retryable {
 		retryInfo := &errdetails.RetryInfo{
-			RetryDelay: durationpb.New(0),
+			RetryDelay: durationpb.New(e.RetryDelay),
 		}
 		if detail, err := connect.NewErrorDetail(retryInfo); err == nil {
 			connectErr.AddDetail(detail)
 		}
+		if e.RetryDelay > 0 {
+			connectErr.Meta().Set(hk.retryAfter, retryAfterSeconds(e.RetryDelay))
+		}
+	}
+
+	if e.HelpURL != "" {
+		description := e.Description
+		if description == "" {
+			description = string(e.Code)
+		}
+		help := &errdetails.Help{
+			Links: []*errdetails.Help_Link{{Description: description, Url: e.HelpURL}},
+		}
+		if detail, err := connect.NewErrorDetail(help); err == nil {
+			connectErr.AddDetail(detail)
+		}
 	}
 }
 
+// retryAfterSeconds formats d as whole seconds, rounded up, as used by the
+// HTTP Retry-After header.
+func retryAfterSeconds(d time.Duration) string {
+	secs := (d + time.Second - 1) / time.Second
+	return strconv.FormatInt(int64(secs), 10)
+}
+
 // FromError extracts error metadata from a *connect.Error's headers/trailers.
 // It reads the configured error code metadata (default "x-error-code") to look up
 // the corresponding Error definition in the Registry.
 func FromError(connectErr *connect.Error) (Error, bool) {
-	if connectErr == nil {
-		return Error{}, false
-	}
-
-	hk := getHeaderKeys()
-	code := connectErr.Meta().Get(hk.errorCode)
-	if code == "" {
-		return Error{}, false
-	}
-
-	return Lookup(ErrorCode(code))
+	return defaultRegistry.FromError(connectErr)
 }
 
 // ExtractErrorCode extracts the domain error code from a *connect.Error's metadata.
@@ -174,12 +214,164 @@ func ExtractRetryInfo(err error) (*errdetails.RetryInfo, bool) {
 	return nil, false
 }
 
+// ExtractQuotaFailure extracts a google.rpc.QuotaFailure detail from a connect.Error, if present.
+// It is attached by WithQuotaViolation and NewQuotaFailure.
+//
+// Example:
+//
+//	if quota, ok := cerr.ExtractQuotaFailure(err); ok {
+//	    for _, v := range quota.Violations {
+//	        fmt.Println(v.Subject, v.Description)
+//	    }
+//	}
+func ExtractQuotaFailure(err error) (*errdetails.QuotaFailure, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if quota, ok := val.(*errdetails.QuotaFailure); ok {
+				return quota, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExtractPreconditionFailure extracts a google.rpc.PreconditionFailure detail
+// from a connect.Error, if present. It is attached by WithPreconditionViolation
+// and NewPreconditionFailure.
+func ExtractPreconditionFailure(err error) (*errdetails.PreconditionFailure, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if failure, ok := val.(*errdetails.PreconditionFailure); ok {
+				return failure, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExtractHelp extracts a google.rpc.Help detail from a connect.Error, if present.
+// It is attached to errors whose definition sets HelpURL.
+func ExtractHelp(err error) (*errdetails.Help, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if help, ok := val.(*errdetails.Help); ok {
+				return help, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExtractDebugInfo extracts a google.rpc.DebugInfo detail from a connect.Error,
+// if present. It is attached by AddDebugInfo and DebugInfoInterceptor.
+//
+// Example:
+//
+//	if info, ok := cerr.ExtractDebugInfo(err); ok {
+//	    log.Println(info.Detail)
+//	}
+func ExtractDebugInfo(err error) (*errdetails.DebugInfo, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if info, ok := val.(*errdetails.DebugInfo); ok {
+				return info, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExtractLocalizedMessage extracts a google.rpc.LocalizedMessage detail from a
+// connect.Error, if present. It is attached by WithLocale and LocaleInterceptor.
+//
+// Example:
+//
+//	msg := err.Message()
+//	if localized, ok := cerr.ExtractLocalizedMessage(err); ok {
+//	    msg = localized.Message
+//	}
+func ExtractLocalizedMessage(err error) (*errdetails.LocalizedMessage, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if msg, ok := val.(*errdetails.LocalizedMessage); ok {
+				return msg, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExtractBadRequest extracts a google.rpc.BadRequest detail from a connect.Error, if present.
+func ExtractBadRequest(err error) (*errdetails.BadRequest, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+	for _, detail := range connectErr.Details() {
+		val, err := detail.Value()
+		if err == nil {
+			if br, ok := val.(*errdetails.BadRequest); ok {
+				return br, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// MatchError reports whether err is a domain error with the given code and
+// ErrorInfo domain. It matches on the (domain, reason) pair of the
+// google.rpc.ErrorInfo detail; an empty domain matches any domain. Errors
+// without an ErrorInfo detail fall back to the error code metadata and only
+// match when domain is empty.
+//
+// Example:
+//
+//	if cerr.MatchError(err, "payments.acme.com", paymentsv1.ErrCardDeclined) { ... }
+func MatchError(err error, domain string, code ErrorCoder) bool {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return false
+	}
+	want := extractCode(code)
+	if info, ok := ExtractErrorInfo(connectErr); ok {
+		return info.Reason == want && (domain == "" || info.Domain == domain)
+	}
+	got, ok := ExtractErrorCode(connectErr)
+	return ok && domain == "" && got == want
+}
+
 // New creates a *connect.Error from a registered error code and template data.
 // It looks up the error definition in the Registry, formats the message template
 // with the provided data, and returns a Connect error with the appropriate status code.
 //
 // The code parameter must implement ErrorCoder (e.g. ErrorCode or *CodedError).
 // If the error code is not found in the Registry, it falls back to CodeInternal.
+// Options such as WithRetryDelay override the registered definition for this call.
 //
 // Example:
 //
@@ -188,18 +380,8 @@ func ExtractRetryInfo(err error) (*errdetails.RetryInfo, bool) {
 //
 //	// Using generated error sentinel
 //	return nil, connecterrors.New(userv1.ErrUserNotFound, connecterrors.M{"id": "123"})
-func New(code ErrorCoder, data M) *connect.Error {
-	codeStr := extractCode(code)
-	e, ok := Lookup(ErrorCode(codeStr))
-	if !ok {
-		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
-	}
-
-	msg := FormatTemplate(e.MessageTpl, data)
-	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg})
-	setMeta(connectErr, e, data)
-
-	return connectErr
+func New(code ErrorCoder, data M, opts ...Option) *connect.Error {
+	return defaultRegistry.New(code, data, opts...)
 }
 
 // extractCode extracts the error code string from an ErrorCoder implementation.
@@ -223,18 +405,8 @@ func extractCode(code ErrorCoder) string {
 //	    "User '{{id}}' does not exist in tenant '{{tenant}}'",
 //	    connecterrors.M{"id": "123", "tenant": "acme"},
 //	)
-func NewWithMessage(code ErrorCoder, customMsg string, data M) *connect.Error {
-	codeStr := extractCode(code)
-	e, ok := Lookup(ErrorCode(codeStr))
-	if !ok {
-		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
-	}
-
-	msg := FormatTemplate(customMsg, data)
-	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg})
-	setMeta(connectErr, e, data)
-
-	return connectErr
+func NewWithMessage(code ErrorCoder, customMsg string, data M, opts ...Option) *connect.Error {
+	return defaultRegistry.NewWithMessage(code, customMsg, data, opts...)
 }
 
 // FromCode creates a *connect.Error directly from a Connect status code and message.
@@ -249,8 +421,10 @@ func FromCode(code connect.Code, msg string) *connect.Error {
 }
 
 // Wrap creates a *connect.Error that wraps an underlying error with context from
-// the Registry. The original error message is preserved and the template message
-// is prepended. This is useful for adding user-facing context to internal errors.
+// the Registry. The error message sent to the client is the public template
+// message only; err stays on the server, reachable through errors.Is, errors.As
+// and CodedError.Unwrap, and is included in CodedError.InternalMessage.
+// This keeps internal details such as database errors out of client responses.
 //
 // The code parameter must implement ErrorCoder (e.g. ErrorCode or *CodedError).
 //
@@ -260,39 +434,20 @@ func FromCode(code connect.Code, msg string) *connect.Error {
 //	if err != nil {
 //	    return nil, connecterrors.Wrap(connecterrors.ErrNotFound, err, connecterrors.M{"id": id})
 //	}
-func Wrap(code ErrorCoder, err error, data M) *connect.Error {
-	codeStr := extractCode(code)
-	e, ok := Lookup(ErrorCode(codeStr))
-	if !ok {
-		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code %s: %w", codeStr, err))
-	}
-
-	msg := FormatTemplate(e.MessageTpl, data)
-	wrapped := fmt.Errorf("%w: %w", &CodedError{code: codeStr, msg: msg}, err)
-	connectErr := connect.NewError(e.ConnectCode, wrapped)
-	setMeta(connectErr, e, data)
-
-	return connectErr
+func Wrap(code ErrorCoder, err error, data M, opts ...Option) *connect.Error {
+	return defaultRegistry.Wrap(code, err, data, opts...)
 }
 
 // IsRetryable checks whether an error code is marked as retryable in the Registry.
 // Returns false if the error code is not found.
 func IsRetryable(code ErrorCode) bool {
-	e, ok := Lookup(code)
-	if !ok {
-		return false
-	}
-	return e.Retryable
+	return defaultRegistry.IsRetryable(code)
 }
 
 // ConnectCode returns the Connect status code for a registered error code.
 // Returns connect.CodeInternal if the error code is not found.
 func ConnectCode(code ErrorCode) connect.Code {
-	e, ok := Lookup(code)
-	if !ok {
-		return connect.CodeInternal
-	}
-	return e.ConnectCode
+	return defaultRegistry.ConnectCode(code)
 }
 
 // Newf creates a *connect.Error from a registered error code with a formatted message.
@@ -300,22 +455,23 @@ func ConnectCode(code ErrorCode) connect.Code {
 // The error code is still used to determine the Connect status code and retryable flag.
 //
 // The code parameter must implement ErrorCoder (e.g. ErrorCode or *CodedError).
+// All args are formatted; use NewfWithOptions to pass options.
 //
 // Example:
 //
 //	return nil, cerr.Newf(cerr.ErrNotFound, "User %q not found in org %s", userID, orgName)
 func Newf(code ErrorCoder, format string, args ...any) *connect.Error {
-	codeStr := extractCode(code)
-	e, ok := Lookup(ErrorCode(codeStr))
-	if !ok {
-		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
-	}
-
-	msg := fmt.Sprintf(format, args...)
-	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg})
-	setMeta(connectErr, e, nil)
+	return defaultRegistry.Newf(code, format, args...)
+}
 
-	return connectErr
+// NewfWithOptions is like Newf, with options applied as in New.
+//
+// Example:
+//
+//	return nil, cerr.NewfWithOptions(cerr.ErrNotFound, []cerr.Option{cerr.WithMeta("x-tenant", tenant)},
+//	    "User %q not found", userID)
+func NewfWithOptions(code ErrorCoder, opts []Option, format string, args ...any) *connect.Error {
+	return defaultRegistry.NewfWithOptions(code, opts, format, args...)
 }
 
 // CodedError is an error type that carries a domain error code alongside
@@ -330,8 +486,13 @@ func Newf(code ErrorCoder, format string, args ...any) *connect.Error {
 //	    fmt.Println(coded.Code()) // "ERROR_NOT_FOUND"
 //	}
 type CodedError struct {
-	code string
-	msg  string
+	code        string
+	msg         string
+	internalMsg string
+	data        M
+	cause       error
+	connectCode connect.Code
+	retryable   bool
 }
 
 // Error implements the error interface.
@@ -346,10 +507,176 @@ func (e *CodedError) Code() string {
 	return e.code
 }
 
+// ConnectCode returns the Connect status code the error was sent with.
+func (e *CodedError) ConnectCode() connect.Code {
+	if e == nil {
+		return connect.CodeUnknown
+	}
+	return e.connectCode
+}
+
+// Retryable reports whether the error was marked as retryable.
+func (e *CodedError) Retryable() bool {
+	return e != nil && e.retryable
+}
+
+// InternalMessage returns the developer-only message of the error: the message
+// set with WithInternalMessage, or else the public message, followed by the
+// message of the cause, if any. It is never sent to clients, except in the
+// google.rpc.DebugInfo detail added by DebugInfoInterceptor.
+//
+// Example:
+//
+//	var coded *cerr.CodedError
+//	if errors.As(err, &coded) {
+//	    log.Printf("%s: %s", coded.Code(), coded.InternalMessage())
+//	}
+func (e *CodedError) InternalMessage() string {
+	if e == nil {
+		return ""
+	}
+	msg := e.internalMsg
+	if msg == "" {
+		msg = e.msg
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the cause recorded with WithCause or Wrap, if any.
+func (e *CodedError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.cause
+}
+
+// Data returns the template data the error was created with.
+// On the client it is decoded from the google.rpc.ErrorInfo metadata.
+// The returned map must not be modified.
+func (e *CodedError) Data() M {
+	if e == nil {
+		return nil
+	}
+	return e.data
+}
+
+// Is reports whether target is an ErrorCode or *CodedError with the same code.
+// This enables errors.Is(err, cerr.ErrNotFound).
+func (e *CodedError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	switch t := target.(type) {
+	case ErrorCode:
+		return string(t) == e.code
+	case *CodedError:
+		return t != nil && t.code == e.code
+	}
+	return false
+}
+
 // ErrorCode returns the domain error code (e.g. "ERROR_NOT_FOUND").
 // Deprecated: Use Code() instead.
 func (e *CodedError) ErrorCode() string { return e.Code() }
 
+// maskedError is an error whose message does not include the error it wraps,
+// so that the wrapped error is not sent to clients.
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string { return e.msg }
+func (e *maskedError) Unwrap() error { return e.err }
+
+// DecodeError decodes the domain error carried by a *connect.Error received by
+// a client. The returned error wraps err unchanged, so errors.As still finds
+// the original *connect.Error (and connect.IsWireError still reports true),
+// and adds a *CodedError reachable through errors.As and errors.Is, e.g.
+// errors.Is(err, cerr.ErrNotFound). The code is read from
+// the error code metadata first and from the google.rpc.ErrorInfo detail
+// second; template data is taken from ErrorInfo.Metadata.
+//
+// Errors that are not *connect.Error, carry no domain code, or already contain a
+// *CodedError are returned unchanged. The Registry is not consulted, so clients
+// can decode codes they have not registered.
+//
+// Example:
+//
+//	err = cerr.DecodeError(err)
+//	var coded *cerr.CodedError
+//	if errors.As(err, &coded) {
+//	    fmt.Println(coded.Code(), coded.Data()["id"])
+//	}
+func DecodeError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err
+	}
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return err
+	}
+
+	code, _ := ExtractErrorCode(connectErr)
+	var data M
+	if info, ok := ExtractErrorInfo(connectErr); ok {
+		if code == "" {
+			code = info.Reason
+		}
+		if len(info.Metadata) > 0 {
+			data = make(M, len(info.Metadata))
+			for k, v := range info.Metadata {
+				data[k] = v
+			}
+		}
+	}
+	if code == "" {
+		return err
+	}
+
+	return &decodedError{err: err, coded: &CodedError{
+		code:        code,
+		msg:         connectErr.Message(),
+		data:        data,
+		connectCode: connectErr.Code(),
+		retryable:   connectErr.Meta().Get(getHeaderKeys().retryable) == "true",
+	}}
+}
+
+// decodedError is returned by DecodeError. It unwraps to both the received
+// error and the *CodedError decoded from it.
+type decodedError struct {
+	err   error
+	coded *CodedError
+}
+
+func (e *decodedError) Error() string   { return e.err.Error() }
+func (e *decodedError) Unwrap() []error { return []error{e.err, e.coded} }
+
+// ErrorData returns the template data carried by err. It prefers the data of a
+// *CodedError in the chain and falls back to the google.rpc.ErrorInfo metadata,
+// so it works for both server-created and client-received errors.
+// Returns nil if err carries no template data.
+//
+// Example:
+//
+//	id := cerr.ErrorData(err)["id"]
+func ErrorData(err error) M {
+	var coded *CodedError
+	if errors.As(err, &coded) && coded.data != nil {
+		return coded.data
+	}
+	info, ok := ExtractErrorInfo(err)
+	if !ok || len(info.Metadata) == 0 {
+		return nil
+	}
+	return M(info.Metadata)
+}
+
 // WithDetails adds structured error details to an existing *connect.Error.
 // Details are protobuf Any messages that can carry domain-specific error information.
 // Returns the same error for method chaining.
@@ -367,3 +694,135 @@ func WithDetails(connectErr *connect.Error, details ...*connect.ErrorDetail) *co
 	}
 	return connectErr
 }
+
+// FromError extracts error metadata from a *connect.Error and resolves its
+// error code against r. See the package-level FromError.
+func (r *Registry) FromError(connectErr *connect.Error) (Error, bool) {
+	if connectErr == nil {
+		return Error{}, false
+	}
+
+	hk := getHeaderKeys()
+	code := connectErr.Meta().Get(hk.errorCode)
+	if code == "" {
+		return Error{}, false
+	}
+
+	return r.Lookup(ErrorCode(code))
+}
+
+// New creates a *connect.Error from an error code registered in r.
+// See the package-level New.
+func (r *Registry) New(code ErrorCoder, data M, opts ...Option) *connect.Error {
+	codeStr := extractCode(code)
+	e, ok := r.Lookup(ErrorCode(codeStr))
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
+	}
+	o := newOptions(opts)
+	e = o.apply(e)
+
+	msg := FormatTemplate(e.MessageTpl, maskSensitive(data, e.SensitiveFields))
+	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg, internalMsg: o.internalMsg, data: data, cause: o.cause, connectCode: e.ConnectCode, retryable: e.Retryable})
+	r.setMeta(connectErr, e, data)
+	if len(o.locales) > 0 {
+		r.addLocalizedMessage(connectErr, e.Code, data, o.locales)
+	}
+	o.finish(connectErr)
+
+	return connectErr
+}
+
+// NewWithMessage creates a *connect.Error from an error code registered in r
+// using a custom message template. See the package-level NewWithMessage.
+func (r *Registry) NewWithMessage(code ErrorCoder, customMsg string, data M, opts ...Option) *connect.Error {
+	codeStr := extractCode(code)
+	e, ok := r.Lookup(ErrorCode(codeStr))
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
+	}
+	o := newOptions(opts)
+	e = o.apply(e)
+
+	msg := FormatTemplate(customMsg, maskSensitive(data, e.SensitiveFields))
+	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg, internalMsg: o.internalMsg, data: data, cause: o.cause, connectCode: e.ConnectCode, retryable: e.Retryable})
+	r.setMeta(connectErr, e, data)
+	if len(o.locales) > 0 {
+		r.addLocalizedMessage(connectErr, e.Code, data, o.locales)
+	}
+	o.finish(connectErr)
+
+	return connectErr
+}
+
+// Wrap creates a *connect.Error from an error code registered in r that wraps err.
+// See the package-level Wrap.
+func (r *Registry) Wrap(code ErrorCoder, err error, data M, opts ...Option) *connect.Error {
+	codeStr := extractCode(code)
+	e, ok := r.Lookup(ErrorCode(codeStr))
+	if !ok {
+		return connect.NewError(connect.CodeInternal, &maskedError{msg: "unknown error code " + codeStr, err: err})
+	}
+	o := newOptions(opts)
+	e = o.apply(e)
+
+	msg := FormatTemplate(e.MessageTpl, maskSensitive(data, e.SensitiveFields))
+	cause := err
+	if o.cause != nil {
+		cause = errors.Join(o.cause, err)
+	}
+	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg, internalMsg: o.internalMsg, data: data, cause: cause, connectCode: e.ConnectCode, retryable: e.Retryable})
+	r.setMeta(connectErr, e, data)
+	if len(o.locales) > 0 {
+		r.addLocalizedMessage(connectErr, e.Code, data, o.locales)
+	}
+	o.finish(connectErr)
+
+	return connectErr
+}
+
+// IsRetryable checks whether an error code is marked as retryable in r.
+func (r *Registry) IsRetryable(code ErrorCode) bool {
+	e, ok := r.Lookup(code)
+	if !ok {
+		return false
+	}
+	return e.Retryable
+}
+
+// ConnectCode returns the Connect status code for an error code registered in r.
+func (r *Registry) ConnectCode(code ErrorCode) connect.Code {
+	e, ok := r.Lookup(code)
+	if !ok {
+		return connect.CodeInternal
+	}
+	return e.ConnectCode
+}
+
+// Newf creates a *connect.Error from an error code registered in r with a
+// fmt.Sprintf-style message. See the package-level Newf.
+func (r *Registry) Newf(code ErrorCoder, format string, args ...any) *connect.Error {
+	return r.NewfWithOptions(code, nil, format, args...)
+}
+
+// NewfWithOptions creates a *connect.Error from an error code registered in r
+// with a fmt.Sprintf-style message and options. See the package-level NewfWithOptions.
+func (r *Registry) NewfWithOptions(code ErrorCoder, opts []Option, format string, args ...any) *connect.Error {
+	codeStr := extractCode(code)
+	e, ok := r.Lookup(ErrorCode(codeStr))
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown error code: %s", codeStr))
+	}
+	o := newOptions(opts)
+	e = o.apply(e)
+
+	msg := fmt.Sprintf(format, args...)
+	connectErr := connect.NewError(e.ConnectCode, &CodedError{code: codeStr, msg: msg, internalMsg: o.internalMsg, cause: o.cause, connectCode: e.ConnectCode, retryable: e.Retryable})
+	r.setMeta(connectErr, e, nil)
+	if len(o.locales) > 0 {
+		r.addLocalizedMessage(connectErr, e.Code, nil, o.locales)
+	}
+	o.finish(connectErr)
+
+	return connectErr
+}
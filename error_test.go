@@ -2,8 +2,10 @@ package connecterrors_test
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 
@@ -49,6 +51,9 @@ func TestNewUnknownCode(t *testing.T) {
 	if err.Code() != connect.CodeInternal {
 		t.Errorf("expected CodeInternal, got %v", err.Code())
 	}
+	if strings.Contains(err.Message(), "fail") {
+		t.Errorf("Message() = %q must not include the wrapped error", err.Message())
+	}
 }
 
 func TestNewRetryableMetadata(t *testing.T) {
@@ -95,12 +100,18 @@ func TestWrap(t *testing.T) {
 	if err.Code() != connect.CodeNotFound {
 		t.Errorf("Code() = %v", err.Code())
 	}
-	msg := err.Error()
-	if !strings.Contains(msg, "Resource '456' not found") {
-		t.Errorf("missing template msg in %q", msg)
+	if msg := err.Message(); msg != "Resource '456' not found" {
+		t.Errorf("Message() = %q, want the template message only", msg)
+	}
+	if !errors.Is(err, orig) {
+		t.Error("expected wrapped error to be reachable via errors.Is")
+	}
+	var coded *connecterrors.CodedError
+	if !errors.As(err, &coded) {
+		t.Fatal("expected *CodedError")
 	}
-	if !strings.Contains(msg, "connection refused") {
-		t.Errorf("missing wrapped error in %q", msg)
+	if got, want := coded.InternalMessage(), "Resource '456' not found: connection refused"; got != want {
+		t.Errorf("InternalMessage() = %q, want %q", got, want)
 	}
 }
 
@@ -423,6 +434,45 @@ func TestExtractRetryInfo(t *testing.T) {
 	}
 }
 
+func TestExtractHelp(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{
+		Code:        "ERROR_DOCUMENTED",
+		MessageTpl:  "documented",
+		ConnectCode: connect.CodeInvalidArgument,
+		Description: "Returned for documented failures.",
+		HelpURL:     "https://docs.acme.com/errors/documented",
+	})
+
+	help, ok := connecterrors.ExtractHelp(reg.New(connecterrors.ErrorCode("ERROR_DOCUMENTED"), nil))
+	if !ok {
+		t.Fatal("expected ExtractHelp to return true")
+	}
+	if len(help.Links) != 1 || help.Links[0].Url != "https://docs.acme.com/errors/documented" ||
+		help.Links[0].Description != "Returned for documented failures." {
+		t.Errorf("unexpected Help detail: %v", help)
+	}
+
+	// No HelpURL, no Help detail
+	if _, ok := connecterrors.ExtractHelp(reg.New(connecterrors.ErrNotFound, nil)); ok {
+		t.Error("expected false for ExtractHelp on ErrNotFound")
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	tests := map[connecterrors.Severity]string{
+		connecterrors.SeverityUnspecified: "unspecified",
+		connecterrors.SeverityWarning:     "warning",
+		connecterrors.SeverityError:       "error",
+		connecterrors.SeverityCritical:    "critical",
+	}
+	for s, want := range tests {
+		if got := s.String(); got != want {
+			t.Errorf("Severity(%d).String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
+
 func TestErrorCodeCode(t *testing.T) {
 	code := connecterrors.ErrorCode("TEST")
 	if code.Code() != "TEST" {
@@ -436,3 +486,250 @@ func TestCodedErrorCodeNil(t *testing.T) {
 		t.Errorf("Code() = %q, want empty string for nil receiver", e.Code())
 	}
 }
+
+func TestErrorsIsErrorCode(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	if !errors.Is(err, connecterrors.ErrNotFound) {
+		t.Error("expected errors.Is to match ErrNotFound")
+	}
+	if errors.Is(err, connecterrors.ErrInternal) {
+		t.Error("expected errors.Is to not match ErrInternal")
+	}
+}
+
+func TestErrorsIsCodedError(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	var target *connecterrors.CodedError
+	if !errors.As(connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "2"}), &target) {
+		t.Fatal("expected *CodedError")
+	}
+	if !errors.Is(err, target) {
+		t.Error("expected errors.Is to match a *CodedError with the same code")
+	}
+	if errors.Is(err, target) != errors.Is(err, connecterrors.ErrNotFound) {
+		t.Error("expected *CodedError and ErrorCode targets to agree")
+	}
+}
+
+func TestCodedErrorData(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "7"})
+	var coded *connecterrors.CodedError
+	if !errors.As(err, &coded) {
+		t.Fatal("expected CodedError")
+	}
+	if coded.Data()["id"] != "7" {
+		t.Errorf("Data()[id] = %q, want 7", coded.Data()["id"])
+	}
+}
+
+func TestDecodeErrorFromErrorInfo(t *testing.T) {
+	// Simulate a client-side error carrying only the ErrorInfo detail.
+	src := connecterrors.New(connecterrors.ErrAlreadyExists, connecterrors.M{"id": "a@b.com"})
+	raw := connect.NewError(src.Code(), errors.New(src.Message()))
+	for _, d := range src.Details() {
+		raw.AddDetail(d)
+	}
+
+	decoded := connecterrors.DecodeError(raw)
+	var coded *connecterrors.CodedError
+	if !errors.As(decoded, &coded) {
+		t.Fatal("expected DecodeError to produce a CodedError")
+	}
+	if coded.Code() != string(connecterrors.ErrAlreadyExists) {
+		t.Errorf("Code() = %q, want %q", coded.Code(), connecterrors.ErrAlreadyExists)
+	}
+	if coded.Data()["id"] != "a@b.com" {
+		t.Errorf("Data()[id] = %q, want a@b.com", coded.Data()["id"])
+	}
+	if connect.CodeOf(decoded) != connect.CodeAlreadyExists {
+		t.Errorf("CodeOf = %v, want CodeAlreadyExists", connect.CodeOf(decoded))
+	}
+}
+
+func TestDecodeErrorKeepsOriginal(t *testing.T) {
+	src := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	raw := connect.NewError(src.Code(), errors.New(src.Message()))
+	for _, d := range src.Details() {
+		raw.AddDetail(d)
+	}
+	wrapped := fmt.Errorf("load profile: %w", raw)
+
+	decoded := connecterrors.DecodeError(wrapped)
+	if !errors.Is(decoded, wrapped) {
+		t.Error("expected the caller's wrapping to be kept")
+	}
+	if decoded.Error() != wrapped.Error() {
+		t.Errorf("Error() = %q, want %q", decoded.Error(), wrapped.Error())
+	}
+	var connectErr *connect.Error
+	if !errors.As(decoded, &connectErr) || connectErr != raw {
+		t.Error("expected errors.As to find the received *connect.Error")
+	}
+	if !errors.Is(decoded, connecterrors.ErrNotFound) {
+		t.Error("expected errors.Is to match ErrNotFound")
+	}
+	if connecterrors.DecodeError(decoded) != decoded {
+		t.Error("expected an already decoded error to be returned unchanged")
+	}
+}
+
+func TestDecodeErrorPassthrough(t *testing.T) {
+	if connecterrors.DecodeError(nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+	plain := errors.New("plain")
+	if connecterrors.DecodeError(plain) != plain {
+		t.Error("expected plain error to be returned unchanged")
+	}
+	raw := connect.NewError(connect.CodeInternal, errors.New("raw"))
+	if connecterrors.DecodeError(raw) != raw {
+		t.Error("expected connect error without domain code to be returned unchanged")
+	}
+}
+
+func TestMatchError(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.SetDomain("payments.acme.com")
+	err := reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+
+	if !connecterrors.MatchError(err, "payments.acme.com", connecterrors.ErrNotFound) {
+		t.Error("expected match on (domain, reason)")
+	}
+	if !connecterrors.MatchError(err, "", connecterrors.ErrNotFound) {
+		t.Error("expected empty domain to match any domain")
+	}
+	if connecterrors.MatchError(err, "users.acme.com", connecterrors.ErrNotFound) {
+		t.Error("expected no match for a different domain")
+	}
+	if connecterrors.MatchError(err, "payments.acme.com", connecterrors.ErrInternal) {
+		t.Error("expected no match for a different reason")
+	}
+	if connecterrors.MatchError(errors.New("plain"), "", connecterrors.ErrNotFound) {
+		t.Error("expected no match for a plain error")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{
+		Code:        "ERROR_RATE_LIMITED",
+		MessageTpl:  "Slow down",
+		ConnectCode: connect.CodeResourceExhausted,
+		Retryable:   true,
+		RetryDelay:  2 * time.Second,
+	})
+
+	err := reg.New(connecterrors.ErrorCode("ERROR_RATE_LIMITED"), nil)
+	info, ok := connecterrors.ExtractRetryInfo(err)
+	if !ok {
+		t.Fatal("expected RetryInfo")
+	}
+	if got := info.RetryDelay.AsDuration(); got != 2*time.Second {
+		t.Errorf("RetryDelay = %v, want 2s", got)
+	}
+	if got := err.Meta().Get("retry-after"); got != "2" {
+		t.Errorf("retry-after = %q, want 2", got)
+	}
+}
+
+func TestWithRetryDelay(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrResourceExhausted, connecterrors.M{"reason": "rate limit"},
+		connecterrors.WithRetryDelay(1500*time.Millisecond),
+	)
+	info, ok := connecterrors.ExtractRetryInfo(err)
+	if !ok {
+		t.Fatal("expected RetryInfo")
+	}
+	if got := info.RetryDelay.AsDuration(); got != 1500*time.Millisecond {
+		t.Errorf("RetryDelay = %v, want 1.5s", got)
+	}
+	// Retry-After is rounded up to whole seconds.
+	if got := err.Meta().Get("retry-after"); got != "2" {
+		t.Errorf("retry-after = %q, want 2", got)
+	}
+
+	// Zero delay does not set the header.
+	err = connecterrors.New(connecterrors.ErrUnavailable, nil)
+	if got := err.Meta().Get("retry-after"); got != "" {
+		t.Errorf("retry-after = %q, want empty", got)
+	}
+}
+
+func TestSetRetryAfterHeaderKey(t *testing.T) {
+	defer connecterrors.SetRetryAfterHeaderKey("retry-after")
+
+	connecterrors.SetRetryAfterHeaderKey("x-retry-after")
+	err := connecterrors.New(connecterrors.ErrUnavailable, nil, connecterrors.WithRetryDelay(time.Second))
+	if got := err.Meta().Get("x-retry-after"); got != "1" {
+		t.Errorf("x-retry-after = %q, want 1", got)
+	}
+}
+
+func TestWithConnectCode(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"}, connecterrors.WithConnectCode(connect.CodePermissionDenied))
+	if err.Code() != connect.CodePermissionDenied {
+		t.Errorf("Code() = %v, want CodePermissionDenied", err.Code())
+	}
+	if got := err.Meta().Get("x-error-code"); got != string(connecterrors.ErrNotFound) {
+		t.Errorf("x-error-code = %q, want %q", got, connecterrors.ErrNotFound)
+	}
+}
+
+func TestWithMetaAndErrorDetails(t *testing.T) {
+	d, derr := connect.NewErrorDetail(&emptypb.Empty{})
+	if derr != nil {
+		t.Fatal(derr)
+	}
+	err := connecterrors.Wrap(connecterrors.ErrInternal, errors.New("boom"), nil,
+		connecterrors.WithMeta("x-request-id", "abc"),
+		connecterrors.WithErrorDetails(d, nil),
+	)
+	if got := err.Meta().Get("x-request-id"); got != "abc" {
+		t.Errorf("x-request-id = %q, want abc", got)
+	}
+	// ErrorInfo + the extra detail.
+	if len(err.Details()) != 2 {
+		t.Errorf("len(Details) = %d, want 2", len(err.Details()))
+	}
+}
+
+func TestWithCause(t *testing.T) {
+	cause := errors.New("no rows")
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"}, connecterrors.WithCause(cause))
+	if !errors.Is(err, cause) {
+		t.Error("expected cause to be reachable via errors.Is")
+	}
+	if strings.Contains(err.Error(), "no rows") {
+		t.Errorf("cause should not change the message, got %q", err.Error())
+	}
+}
+
+func TestNewfWithOptions(t *testing.T) {
+	err := connecterrors.NewfWithOptions(connecterrors.ErrNotFound, []connecterrors.Option{connecterrors.WithMeta("x-tenant", "acme")}, "user %s gone", "alice")
+	if err.Message() != "user alice gone" {
+		t.Errorf("Message() = %q, want user alice gone", err.Message())
+	}
+	if got := err.Meta().Get("x-tenant"); got != "acme" {
+		t.Errorf("x-tenant = %q, want acme", got)
+	}
+}
+
+func TestErrorData(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "9"})
+	if got := connecterrors.ErrorData(err)["id"]; got != "9" {
+		t.Errorf("ErrorData()[id] = %q, want 9", got)
+	}
+
+	// Client-side error without a CodedError falls back to ErrorInfo.Metadata.
+	raw := connect.NewError(err.Code(), errors.New(err.Message()))
+	for _, d := range err.Details() {
+		raw.AddDetail(d)
+	}
+	if got := connecterrors.ErrorData(raw)["id"]; got != "9" {
+		t.Errorf("ErrorData(raw)[id] = %q, want 9", got)
+	}
+
+	if connecterrors.ErrorData(errors.New("plain")) != nil {
+		t.Error("expected nil data for a plain error")
+	}
+}
@@ -21,6 +21,8 @@ func init() {
 		MessageTpl:  "Invalid credentials for user '{{email}}'",
 		ConnectCode: connect.CodeUnauthenticated,
 		Retryable:   false,
+
+		SensitiveFields: []string{"email"},
 	})
 	cerr.Register(cerr.Error{
 		Code:        ErrAccountLocked,
@@ -20,7 +20,7 @@ func init() {
 	cerr.RegisterAll([]cerr.Error{
 		{
 			Code:        ErrInsufficientFunds,
-			MessageTpl:  "Insufficient funds: requested {{amount}}, available {{balance}}",
+			MessageTpl:  "Insufficient funds: requested {{amount:decimal}}, available {{balance:decimal}}",
 			ConnectCode: connect.CodeFailedPrecondition,
 			Retryable:   false,
 		},
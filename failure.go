@@ -0,0 +1,47 @@
+package connecterrors
+
+import "connectrpc.com/connect"
+
+// NewQuotaFailure creates an ErrResourceExhausted error with a
+// google.rpc.QuotaFailure detail holding one violation for subject. The
+// description also fills the {{reason}} of the message. Add further violations
+// with WithQuotaViolation.
+//
+// Example:
+//
+//	if !limiter.Allow(userID) {
+//	    return nil, cerr.NewQuotaFailure("user:"+userID, "Rate limit of 10 requests per second exceeded",
+//	        cerr.WithRetryDelay(limiter.Reset()),
+//	    )
+//	}
+func NewQuotaFailure(subject, description string, opts ...Option) *connect.Error {
+	return defaultRegistry.NewQuotaFailure(subject, description, opts...)
+}
+
+// NewQuotaFailure creates an ErrResourceExhausted error registered in r with a
+// google.rpc.QuotaFailure detail. See the package-level NewQuotaFailure.
+func (r *Registry) NewQuotaFailure(subject, description string, opts ...Option) *connect.Error {
+	opts = append([]Option{WithQuotaViolation(subject, description)}, opts...)
+	return r.New(ErrResourceExhausted, M{"reason": description}, opts...)
+}
+
+// NewPreconditionFailure creates an ErrFailedPrecondition error with a
+// google.rpc.PreconditionFailure detail holding one violation. The description
+// also fills the {{reason}} of the message. Add further violations with
+// WithPreconditionViolation.
+//
+// Example:
+//
+//	if !account.TermsAccepted {
+//	    return nil, cerr.NewPreconditionFailure("TOS", "user:"+userID, "Terms of service not accepted")
+//	}
+func NewPreconditionFailure(typ, subject, description string, opts ...Option) *connect.Error {
+	return defaultRegistry.NewPreconditionFailure(typ, subject, description, opts...)
+}
+
+// NewPreconditionFailure creates an ErrFailedPrecondition error registered in r
+// with a google.rpc.PreconditionFailure detail. See the package-level NewPreconditionFailure.
+func (r *Registry) NewPreconditionFailure(typ, subject, description string, opts ...Option) *connect.Error {
+	opts = append([]Option{WithPreconditionViolation(typ, subject, description)}, opts...)
+	return r.New(ErrFailedPrecondition, M{"reason": description}, opts...)
+}
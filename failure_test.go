@@ -0,0 +1,79 @@
+package connecterrors_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func TestNewQuotaFailure(t *testing.T) {
+	err := connecterrors.NewQuotaFailure("user:42", "Rate limit exceeded",
+		connecterrors.WithQuotaViolation("project:7", "Project quota exceeded"),
+		connecterrors.WithRetryDelay(time.Second),
+	)
+
+	if err.Code() != connect.CodeResourceExhausted {
+		t.Errorf("Code = %v, want resource_exhausted", err.Code())
+	}
+	if err.Message() != "Resource exhausted: Rate limit exceeded" {
+		t.Errorf("Message = %q", err.Message())
+	}
+	quota, ok := connecterrors.ExtractQuotaFailure(err)
+	if !ok {
+		t.Fatal("expected QuotaFailure detail")
+	}
+	if len(quota.Violations) != 2 || quota.Violations[0].Subject != "user:42" || quota.Violations[1].Description != "Project quota exceeded" {
+		t.Errorf("unexpected violations: %v", quota.Violations)
+	}
+	if _, ok := connecterrors.ExtractErrorInfo(err); !ok {
+		t.Error("expected ErrorInfo detail alongside QuotaFailure")
+	}
+	if _, ok := connecterrors.ExtractRetryInfo(err); !ok {
+		t.Error("expected RetryInfo detail alongside QuotaFailure")
+	}
+}
+
+func TestNewPreconditionFailure(t *testing.T) {
+	err := connecterrors.NewPreconditionFailure("TOS", "user:42", "Terms of service not accepted")
+
+	if !errors.Is(err, connecterrors.ErrFailedPrecondition) {
+		t.Errorf("expected ErrFailedPrecondition, got %v", err)
+	}
+	failure, ok := connecterrors.ExtractPreconditionFailure(err)
+	if !ok {
+		t.Fatal("expected PreconditionFailure detail")
+	}
+	if len(failure.Violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(failure.Violations))
+	}
+	if v := failure.Violations[0]; v.Type != "TOS" || v.Subject != "user:42" || v.Description != "Terms of service not accepted" {
+		t.Errorf("unexpected violation: %v", v)
+	}
+}
+
+func TestViolationOptionsWithNew(t *testing.T) {
+	err := connecterrors.New(connecterrors.ErrFailedPrecondition, connecterrors.M{"reason": "not ready"},
+		connecterrors.WithPreconditionViolation("STATE", "order:1", "Order is not paid"),
+		connecterrors.WithPreconditionViolation("STATE", "order:1", "Order is not shipped"),
+	)
+
+	var n int
+	for _, d := range err.Details() {
+		if d.Type() == "google.rpc.PreconditionFailure" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("got %d PreconditionFailure details, want 1", n)
+	}
+	if failure, _ := connecterrors.ExtractPreconditionFailure(err); len(failure.GetViolations()) != 2 {
+		t.Errorf("got %d violations, want 2", len(failure.GetViolations()))
+	}
+	if _, ok := connecterrors.ExtractQuotaFailure(err); ok {
+		t.Error("unexpected QuotaFailure detail")
+	}
+}
@@ -3,6 +3,7 @@ package connecterrors
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"connectrpc.com/connect"
 )
@@ -14,10 +15,10 @@ import (
 // Common use cases: logging, metrics, tracing, error transformation.
 type ErrorInterceptorFunc func(ctx context.Context, connectErr *connect.Error, def Error)
 
-// ErrorInterceptor is a server-side Connect interceptor that hooks into
-// error responses. When a handler returns a *connect.Error with an
-// "x-error-code" metadata value, the interceptor resolves it from the
-// Registry and invokes the callback.
+// ErrorInterceptor is a Connect interceptor that hooks into error responses
+// for unary and streaming RPCs. When a handler returns a *connect.Error with an
+// "x-error-code" metadata value, or a streaming Send/Receive (or a client's
+// CloseResponse) fails with one, the interceptor resolves it from the Registry and invokes the callback.
 //
 // Example:
 //
@@ -33,21 +34,223 @@ type ErrorInterceptorFunc func(ctx context.Context, connectErr *connect.Error, d
 //	mux.Handle(userv1connect.NewUserServiceHandler(svc,
 //	    connect.WithInterceptors(interceptor),
 //	))
-func ErrorInterceptor(fn ErrorInterceptorFunc) connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			resp, err := next(ctx, req)
-			if err != nil {
-				var connectErr *connect.Error
-				if ok := asConnectError(err, &connectErr); ok {
-					if def, found := FromError(connectErr); found {
-						fn(ctx, connectErr, def)
-					}
-				}
-			}
-			return resp, err
+func ErrorInterceptor(fn ErrorInterceptorFunc) connect.Interceptor {
+	return defaultRegistry.ErrorInterceptor(fn)
+}
+
+// ErrorInterceptor returns a Connect interceptor that resolves domain errors
+// against r. See the package-level ErrorInterceptor.
+func (r *Registry) ErrorInterceptor(fn ErrorInterceptorFunc) connect.Interceptor {
+	return &errorInterceptor{reg: r, fn: fn}
+}
+
+// errorInterceptor implements connect.Interceptor for ErrorInterceptor.
+type errorInterceptor struct {
+	reg *Registry
+	fn  ErrorInterceptorFunc
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *errorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil {
+			i.report(ctx, err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *errorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &errorClientConn{
+			StreamingClientConn: next(ctx, spec),
+			tracker:             errorTracker{ctx: ctx, report: i.report},
+		}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *errorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		wrapped := &errorHandlerConn{
+			StreamingHandlerConn: conn,
+			tracker:              errorTracker{ctx: ctx, report: i.report},
+		}
+		err := next(ctx, wrapped)
+		if err != nil {
+			wrapped.tracker.observe(err)
+		}
+		return err
+	}
+}
+
+// report resolves err against the interceptor's Registry and invokes the callback
+// if it carries a registered domain error code.
+func (i *errorInterceptor) report(ctx context.Context, err error) {
+	var connectErr *connect.Error
+	if ok := asConnectError(err, &connectErr); ok {
+		if def, found := i.reg.FromError(connectErr); found {
+			i.fn(ctx, connectErr, def)
+		}
+	}
+}
+
+// errorTracker reports each distinct stream error exactly once, so that an
+// error surfaced by Send/Receive and then returned by the handler is not
+// reported twice. It is safe for concurrent use by bidi streams.
+type errorTracker struct {
+	ctx    context.Context
+	report func(context.Context, error)
+
+	mu   sync.Mutex
+	seen []*connect.Error
+}
+
+// observe reports err unless the same *connect.Error was already reported.
+func (t *errorTracker) observe(err error) {
+	var connectErr *connect.Error
+	if !asConnectError(err, &connectErr) {
+		return
+	}
+	t.mu.Lock()
+	for _, s := range t.seen {
+		if s == connectErr {
+			t.mu.Unlock()
+			return
 		}
 	}
+	t.seen = append(t.seen, connectErr)
+	t.mu.Unlock()
+	t.report(t.ctx, err)
+}
+
+// errorHandlerConn wraps a server-side stream to observe Send/Receive errors.
+type errorHandlerConn struct {
+	connect.StreamingHandlerConn
+	tracker errorTracker
+}
+
+// Receive implements connect.StreamingHandlerConn.
+func (c *errorHandlerConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err != nil {
+		c.tracker.observe(err)
+	}
+	return err
+}
+
+// Send implements connect.StreamingHandlerConn.
+func (c *errorHandlerConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if err != nil {
+		c.tracker.observe(err)
+	}
+	return err
+}
+
+// errorClientConn wraps a client-side stream to observe Send, Receive and
+// CloseResponse errors.
+type errorClientConn struct {
+	connect.StreamingClientConn
+	tracker errorTracker
+}
+
+// Receive implements connect.StreamingClientConn.
+func (c *errorClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil {
+		c.tracker.observe(err)
+	}
+	return err
+}
+
+// Send implements connect.StreamingClientConn.
+func (c *errorClientConn) Send(msg any) error {
+	err := c.StreamingClientConn.Send(msg)
+	if err != nil {
+		c.tracker.observe(err)
+	}
+	return err
+}
+
+// CloseResponse implements connect.StreamingClientConn.
+func (c *errorClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	if err != nil {
+		c.tracker.observe(err)
+	}
+	return err
+}
+
+// ClientErrorInterceptor is a client-side Connect interceptor that decodes
+// domain errors returned by the server. Every *connect.Error surfaced by a
+// unary call or a streaming Send/Receive is passed through DecodeError, so
+// callers can use errors.As and errors.Is instead of parsing metadata by hand.
+// The received *connect.Error stays reachable through errors.As.
+//
+// Example:
+//
+//	client := userv1connect.NewUserServiceClient(http.DefaultClient, url,
+//	    connect.WithInterceptors(cerr.ClientErrorInterceptor()),
+//	)
+//
+//	_, err := client.GetUser(ctx, req)
+//	if errors.Is(err, userv1.ErrUserNotFound) { ... }
+//
+//	var coded *cerr.CodedError
+//	if errors.As(err, &coded) {
+//	    fmt.Println(coded.Data()["id"])
+//	}
+func ClientErrorInterceptor() connect.Interceptor {
+	return &clientErrorInterceptor{}
+}
+
+// clientErrorInterceptor implements connect.Interceptor for ClientErrorInterceptor.
+type clientErrorInterceptor struct{}
+
+// WrapUnary implements connect.Interceptor.
+func (i *clientErrorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil && req.Spec().IsClient {
+			err = DecodeError(err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *clientErrorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &decodingClientConn{StreamingClientConn: next(ctx, spec)}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor. Handlers are passed through unchanged.
+func (i *clientErrorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// decodingClientConn wraps a client-side stream to decode Send/Receive errors.
+type decodingClientConn struct {
+	connect.StreamingClientConn
+}
+
+// Receive implements connect.StreamingClientConn.
+func (c *decodingClientConn) Receive(msg any) error {
+	return DecodeError(c.StreamingClientConn.Receive(msg))
+}
+
+// Send implements connect.StreamingClientConn.
+func (c *decodingClientConn) Send(msg any) error {
+	return DecodeError(c.StreamingClientConn.Send(msg))
+}
+
+// CloseResponse implements connect.StreamingClientConn.
+func (c *decodingClientConn) CloseResponse() error {
+	return DecodeError(c.StreamingClientConn.CloseResponse())
 }
 
 // asConnectError attempts to extract a *connect.Error from err using errors.As,
@@ -2,9 +2,13 @@ package connecterrors_test
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	connecterrors "github.com/balcieren/connect-errors-go"
 )
@@ -21,7 +25,7 @@ func TestErrorInterceptor(t *testing.T) {
 	// Simulate a handler that returns a domain error
 	domainErr := connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"resource": "user", "id": "42"})
 
-	handler := interceptor(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
 		return nil, domainErr
 	})
 
@@ -46,7 +50,7 @@ func TestErrorInterceptorNoMeta(t *testing.T) {
 
 	// Raw connect.Error without x-error-code metadata
 	rawErr := connect.NewError(connect.CodeInternal, nil)
-	handler := interceptor(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
 		return nil, rawErr
 	})
 
@@ -62,7 +66,7 @@ func TestErrorInterceptorNoError(t *testing.T) {
 		called = true
 	})
 
-	handler := interceptor(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
 		return nil, nil
 	})
 
@@ -74,3 +78,195 @@ func TestErrorInterceptorNoError(t *testing.T) {
 		t.Error("interceptor should not be called when there is no error")
 	}
 }
+
+// fakeHandlerConn is a minimal connect.StreamingHandlerConn for tests.
+type fakeHandlerConn struct {
+	connect.StreamingHandlerConn
+	sendErr error
+}
+
+func (c *fakeHandlerConn) Send(any) error               { return c.sendErr }
+func (c *fakeHandlerConn) Receive(any) error            { return nil }
+func (c *fakeHandlerConn) RequestHeader() http.Header   { return http.Header{} }
+func (c *fakeHandlerConn) ResponseHeader() http.Header  { return http.Header{} }
+func (c *fakeHandlerConn) ResponseTrailer() http.Header { return http.Header{} }
+func (c *fakeHandlerConn) Spec() connect.Spec           { return connect.Spec{} }
+
+func TestErrorInterceptorStreamingHandlerReturn(t *testing.T) {
+	var calls int
+	var captured connecterrors.Error
+	interceptor := connecterrors.ErrorInterceptor(func(_ context.Context, _ *connect.Error, def connecterrors.Error) {
+		calls++
+		captured = def
+	})
+
+	domainErr := connecterrors.New(connecterrors.ErrUnavailable, nil)
+	handler := interceptor.WrapStreamingHandler(func(_ context.Context, _ connect.StreamingHandlerConn) error {
+		return domainErr
+	})
+
+	if err := handler(context.Background(), &fakeHandlerConn{}); !errors.Is(err, domainErr) {
+		t.Fatalf("expected domain error to be returned, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("callback calls = %d, want 1", calls)
+	}
+	if captured.Code != connecterrors.ErrUnavailable {
+		t.Errorf("captured code = %q, want %q", captured.Code, connecterrors.ErrUnavailable)
+	}
+}
+
+func TestErrorInterceptorStreamingHandlerSend(t *testing.T) {
+	var calls int
+	interceptor := connecterrors.ErrorInterceptor(func(_ context.Context, _ *connect.Error, _ connecterrors.Error) {
+		calls++
+	})
+
+	sendErr := connecterrors.New(connecterrors.ErrAborted, connecterrors.M{"reason": "conflict"})
+	handler := interceptor.WrapStreamingHandler(func(_ context.Context, conn connect.StreamingHandlerConn) error {
+		// Returning the Send error must not report it a second time.
+		return conn.Send(nil)
+	})
+
+	_ = handler(context.Background(), &fakeHandlerConn{sendErr: sendErr})
+	if calls != 1 {
+		t.Errorf("callback calls = %d, want 1", calls)
+	}
+}
+
+// fakeClientConn is a minimal connect.StreamingClientConn for tests.
+type fakeClientConn struct {
+	connect.StreamingClientConn
+	sendErr, receiveErr, closeErr error
+}
+
+func (c *fakeClientConn) Send(any) error       { return c.sendErr }
+func (c *fakeClientConn) Receive(any) error    { return c.receiveErr }
+func (c *fakeClientConn) CloseResponse() error { return c.closeErr }
+func (c *fakeClientConn) Spec() connect.Spec   { return connect.Spec{IsClient: true} }
+
+func TestErrorInterceptorStreamingClient(t *testing.T) {
+	var captured []connecterrors.ErrorCode
+	interceptor := connecterrors.ErrorInterceptor(func(_ context.Context, _ *connect.Error, def connecterrors.Error) {
+		captured = append(captured, def.Code)
+	})
+
+	receiveErr := connecterrors.New(connecterrors.ErrUnavailable, nil)
+	fake := &fakeClientConn{
+		sendErr:    connecterrors.New(connecterrors.ErrAborted, connecterrors.M{"reason": "conflict"}),
+		receiveErr: receiveErr,
+		closeErr:   connecterrors.New(connecterrors.ErrDeadlineExceeded, nil),
+	}
+	conn := interceptor.WrapStreamingClient(func(context.Context, connect.Spec) connect.StreamingClientConn {
+		return fake
+	})(context.Background(), connect.Spec{IsClient: true})
+
+	if err := conn.Send(nil); !errors.Is(err, fake.sendErr) {
+		t.Errorf("Send = %v, want the Send error", err)
+	}
+	if err := conn.Receive(nil); !errors.Is(err, receiveErr) {
+		t.Errorf("Receive = %v, want the Receive error", err)
+	}
+	// Receiving the same error again must not report it a second time.
+	_ = conn.Receive(nil)
+	if err := conn.CloseResponse(); !errors.Is(err, fake.closeErr) {
+		t.Errorf("CloseResponse = %v, want the CloseResponse error", err)
+	}
+
+	want := []connecterrors.ErrorCode{connecterrors.ErrAborted, connecterrors.ErrUnavailable, connecterrors.ErrDeadlineExceeded}
+	if len(captured) != len(want) {
+		t.Fatalf("captured = %v, want %v", captured, want)
+	}
+	for i := range want {
+		if captured[i] != want[i] {
+			t.Errorf("captured[%d] = %q, want %q", i, captured[i], want[i])
+		}
+	}
+}
+
+func TestErrorInterceptorStreamingHandlerNoError(t *testing.T) {
+	called := false
+	interceptor := connecterrors.ErrorInterceptor(func(_ context.Context, _ *connect.Error, _ connecterrors.Error) {
+		called = true
+	})
+
+	handler := interceptor.WrapStreamingHandler(func(_ context.Context, conn connect.StreamingHandlerConn) error {
+		return conn.Send(nil)
+	})
+
+	if err := handler(context.Background(), &fakeHandlerConn{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if called {
+		t.Error("interceptor should not be called when there is no error")
+	}
+}
+
+func TestClientErrorInterceptor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Get", connect.NewUnaryHandler(
+		"/test.v1.TestService/Get",
+		func(_ context.Context, _ *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			return nil, connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "42"})
+		},
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](
+		srv.Client(),
+		srv.URL+"/test.v1.TestService/Get",
+		connect.WithInterceptors(connecterrors.ClientErrorInterceptor()),
+	)
+
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !errors.Is(err, connecterrors.ErrNotFound) {
+		t.Errorf("expected errors.Is(err, ErrNotFound), got %v", err)
+	}
+	if errors.Is(err, connecterrors.ErrInternal) {
+		t.Error("expected errors.Is(err, ErrInternal) to be false")
+	}
+	if !connecterrors.MatchError(err, "", connecterrors.ErrNotFound) {
+		t.Errorf("expected MatchError(err, ErrNotFound), got %v", err)
+	}
+
+	var coded *connecterrors.CodedError
+	if !errors.As(err, &coded) {
+		t.Fatal("expected errors.As to extract CodedError on the client")
+	}
+	if coded.Data()["id"] != "42" {
+		t.Errorf("Data()[id] = %q, want 42", coded.Data()["id"])
+	}
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("CodeOf = %v, want CodeNotFound", connect.CodeOf(err))
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || !connect.IsWireError(connectErr) {
+		t.Error("expected the received wire error to be kept")
+	}
+	if _, ok := connecterrors.ExtractErrorInfo(err); !ok {
+		t.Error("expected ErrorInfo detail to be preserved")
+	}
+}
+
+func TestRegistryErrorInterceptor(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{Code: "ERROR_REG_ONLY", MessageTpl: "x", ConnectCode: connect.CodeInternal})
+
+	var captured connecterrors.Error
+	interceptor := reg.ErrorInterceptor(func(_ context.Context, _ *connect.Error, def connecterrors.Error) {
+		captured = def
+	})
+
+	handler := interceptor.WrapUnary(func(_ context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, reg.New(connecterrors.ErrorCode("ERROR_REG_ONLY"), nil)
+	})
+
+	_, _ = handler(context.Background(), nil)
+	if captured.Code != "ERROR_REG_ONLY" {
+		t.Errorf("captured code = %q, want ERROR_REG_ONLY", captured.Code)
+	}
+}
@@ -0,0 +1,206 @@
+// Package errordef reads connecterrors.v1 error definitions from the options
+// of proto file and method descriptors. It is shared by protoc-gen-connect-errors-go,
+// protoc-gen-connect-errors-ts and the connect-errors CLI.
+package errordef
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	connecterrorspb "github.com/balcieren/connect-errors-go/proto/connecterrors"
+)
+
+// Def is a single validated ErrorDef read from descriptor options.
+type Def struct {
+	Code        string
+	Message     string
+	ConnectCode int
+	Retryable   bool
+	Domain      string
+	RetryDelay  time.Duration
+	Description string
+	HelpURL     string
+	Severity    int
+	Deprecated  bool
+	ReplacedBy  string
+
+	// LocalizedMessages maps BCP 47 language tags to message templates.
+	LocalizedMessages map[string]string
+
+	// SensitiveFields lists the placeholders whose values must not reach clients.
+	SensitiveFields []string
+}
+
+// codePattern matches error codes that map to valid Go and TypeScript identifiers.
+var codePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// localePattern matches BCP 47 language tags such as "de" or "pt-BR".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// FileErrors returns the file-level error definitions (connecterrors.v1.error)
+// and the file-level default error domain (connecterrors.v1.error_domain)
+// declared in opts. It returns an error if the options are malformed or a
+// definition is invalid.
+func FileErrors(opts *descriptorpb.FileOptions) ([]Def, string, error) {
+	if opts == nil {
+		return nil, "", nil
+	}
+	resolved := &descriptorpb.FileOptions{}
+	if err := resolve(opts, resolved); err != nil {
+		return nil, "", err
+	}
+	defs, err := newDefs(proto.GetExtension(resolved, connecterrorspb.E_Error).([]*connecterrorspb.ErrorDef))
+	if err != nil {
+		return nil, "", err
+	}
+	return defs, proto.GetExtension(resolved, connecterrorspb.E_ErrorDomain).(string), nil
+}
+
+// MethodErrors returns the method-level error definitions (connecterrors.v1.connect_error)
+// declared in opts. It returns an error if the options are malformed or a
+// definition is invalid.
+func MethodErrors(opts *descriptorpb.MethodOptions) ([]Def, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	resolved := &descriptorpb.MethodOptions{}
+	if err := resolve(opts, resolved); err != nil {
+		return nil, err
+	}
+	return newDefs(proto.GetExtension(resolved, connecterrorspb.E_ConnectError).([]*connecterrorspb.ErrorDef))
+}
+
+// resolve copies src into dst so that extensions left as unknown fields are
+// parsed into connecterrorspb types. This happens when src was decoded without
+// the extensions registered, e.g. in a descriptor set read by another tool.
+func resolve(src, dst proto.Message) error {
+	b, err := proto.Marshal(src)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("malformed connecterrors.v1 options: %w", err)
+	}
+	return nil
+}
+
+// newDefs validates and converts ErrorDef messages.
+func newDefs(pbs []*connecterrorspb.ErrorDef) ([]Def, error) {
+	var defs []Def
+	for _, pb := range pbs {
+		d, err := newDef(pb)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, d)
+	}
+	return defs, nil
+}
+
+// newDef validates and converts a single ErrorDef message.
+func newDef(pb *connecterrorspb.ErrorDef) (Def, error) {
+	code := pb.GetCode()
+	if code == "" {
+		return Def{}, fmt.Errorf("error definition %q has no code", pb.GetMessage())
+	}
+	if !codePattern.MatchString(code) {
+		return Def{}, fmt.Errorf("invalid error code %q: must start with a letter and contain only letters, digits and underscores", code)
+	}
+	if _, ok := connecterrorspb.Code_name[int32(pb.GetConnectCode())]; !ok {
+		return Def{}, fmt.Errorf("error %s: unknown connect_code %d", code, pb.GetConnectCode())
+	}
+	if err := checkParamTypes(pb.GetMessage()); err != nil {
+		return Def{}, fmt.Errorf("error %s: %w", code, err)
+	}
+
+	var delay time.Duration
+	if d := pb.GetRetryDelay(); d != nil {
+		if err := d.CheckValid(); err != nil {
+			return Def{}, fmt.Errorf("error %s: invalid retry_delay: %w", code, err)
+		}
+		if delay = d.AsDuration(); delay < 0 {
+			return Def{}, fmt.Errorf("error %s: retry_delay must not be negative", code)
+		}
+	}
+
+	if _, ok := connecterrorspb.Severity_name[int32(pb.GetSeverity())]; !ok {
+		return Def{}, fmt.Errorf("error %s: unknown severity %d", code, pb.GetSeverity())
+	}
+	if h := pb.GetHelpUrl(); h != "" {
+		if u, err := url.Parse(h); err != nil || !u.IsAbs() {
+			return Def{}, fmt.Errorf("error %s: help_url %q is not an absolute URL", code, h)
+		}
+	}
+	if r := pb.GetReplacedBy(); r != "" {
+		if !pb.GetDeprecated() {
+			return Def{}, fmt.Errorf("error %s: replaced_by requires deprecated = true", code)
+		}
+		if !codePattern.MatchString(r) || r == code {
+			return Def{}, fmt.Errorf("error %s: invalid replaced_by %q", code, r)
+		}
+	}
+
+	var localized map[string]string
+	if msgs := pb.GetLocalizedMessages(); len(msgs) > 0 {
+		declared := make(map[string]bool)
+		for _, f := range Fields(pb.GetMessage()) {
+			declared[f] = true
+		}
+		localized = make(map[string]string, len(msgs))
+		for locale, msg := range msgs {
+			if !localePattern.MatchString(locale) {
+				return Def{}, fmt.Errorf("error %s: invalid locale %q in localized_messages", code, locale)
+			}
+			if msg == "" {
+				return Def{}, fmt.Errorf("error %s: empty localized message for %s", code, locale)
+			}
+			for _, f := range Fields(msg) {
+				if !declared[f] {
+					return Def{}, fmt.Errorf("error %s: localized message for %s uses {{%s}}, which message does not declare", code, locale, f)
+				}
+			}
+			localized[locale] = msg
+		}
+	}
+
+	var sensitive []string
+	if fields := pb.GetSensitiveFields(); len(fields) > 0 {
+		declared := make(map[string]bool)
+		for _, f := range Fields(pb.GetMessage()) {
+			declared[f] = true
+		}
+		seen := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			if !declared[f] {
+				return Def{}, fmt.Errorf("error %s: sensitive field %q is not a placeholder of message", code, f)
+			}
+			if seen[f] {
+				return Def{}, fmt.Errorf("error %s: duplicate sensitive field %q", code, f)
+			}
+			seen[f] = true
+		}
+		sensitive = append([]string(nil), fields...)
+	}
+
+	return Def{
+		Code:        code,
+		Message:     pb.GetMessage(),
+		ConnectCode: int(pb.GetConnectCode()),
+		Retryable:   pb.GetRetryable(),
+		Domain:      pb.GetDomain(),
+		RetryDelay:  delay,
+		Description: pb.GetDescription(),
+		HelpURL:     pb.GetHelpUrl(),
+		Severity:    int(pb.GetSeverity()),
+		Deprecated:  pb.GetDeprecated(),
+		ReplacedBy:  pb.GetReplacedBy(),
+
+		LocalizedMessages: localized,
+		SensitiveFields:   sensitive,
+	}, nil
+}
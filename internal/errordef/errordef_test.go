@@ -0,0 +1,141 @@
+package errordef
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	connecterrorspb "github.com/balcieren/connect-errors-go/proto/connecterrors"
+)
+
+func TestFileErrors(t *testing.T) {
+	opts := &descriptorpb.FileOptions{GoPackage: proto.String("x")}
+	proto.SetExtension(opts, connecterrorspb.E_Error, []*connecterrorspb.ErrorDef{{
+		Code:        "ERROR_TEST",
+		Message:     "msg {{id}}",
+		ConnectCode: connecterrorspb.Code_CODE_NOT_FOUND,
+		Retryable:   true,
+		Domain:      "a.b",
+		RetryDelay:  durationpb.New(30*time.Second + 500*time.Millisecond),
+		LocalizedMessages: map[string]string{
+			"de": "Nachricht {{id}}",
+		},
+		SensitiveFields: []string{"id"},
+	}})
+	proto.SetExtension(opts, connecterrorspb.E_ErrorDomain, "acme.com")
+
+	defs, domain, err := FileErrors(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Def{
+		Code:        "ERROR_TEST",
+		Message:     "msg {{id}}",
+		ConnectCode: 5,
+		Retryable:   true,
+		Domain:      "a.b",
+		RetryDelay:  30*time.Second + 500*time.Millisecond,
+
+		LocalizedMessages: map[string]string{"de": "Nachricht {{id}}"},
+		SensitiveFields:   []string{"id"},
+	}
+	if len(defs) != 1 || !reflect.DeepEqual(defs[0], want) {
+		t.Errorf("FileErrors = %+v, want [%+v]", defs, want)
+	}
+	if domain != "acme.com" {
+		t.Errorf("domain = %q, want acme.com", domain)
+	}
+
+	if defs, domain, err := FileErrors(nil); defs != nil || domain != "" || err != nil {
+		t.Error("expected no definitions for nil options")
+	}
+}
+
+func TestMethodErrors(t *testing.T) {
+	opts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(opts, connecterrorspb.E_ConnectError, []*connecterrorspb.ErrorDef{
+		{Code: "ERROR_A", ConnectCode: connecterrorspb.Code_CODE_INTERNAL},
+		{Code: "ERROR_B", ConnectCode: connecterrorspb.Code_CODE_UNAVAILABLE},
+	})
+
+	defs, err := MethodErrors(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 2 || defs[0].Code != "ERROR_A" || defs[1].ConnectCode != 14 {
+		t.Errorf("MethodErrors = %+v", defs)
+	}
+}
+
+// Options decoded without the extensions registered carry them as unknown fields.
+func TestUnknownFieldOptions(t *testing.T) {
+	var def []byte
+	def = protowire.AppendTag(def, 1, protowire.BytesType)
+	def = protowire.AppendString(def, "ERROR_X")
+
+	raw := protowire.AppendTag(nil, 50002, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, def)
+	raw = protowire.AppendTag(raw, 50003, protowire.BytesType)
+	raw = protowire.AppendString(raw, "acme.com")
+	opts := &descriptorpb.FileOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+
+	defs, domain, err := FileErrors(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 1 || defs[0].Code != "ERROR_X" || domain != "acme.com" {
+		t.Errorf("FileErrors = %v, %q", defs, domain)
+	}
+}
+
+func TestMalformedOptions(t *testing.T) {
+	raw := protowire.AppendTag(nil, 50001, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, []byte{0x0a, 0x05, 'E'}) // truncated code
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(raw)
+
+	if _, err := MethodErrors(opts); err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("MethodErrors error = %v, want malformed options", err)
+	}
+}
+
+func TestInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		def  *connecterrorspb.ErrorDef
+		want string
+	}{
+		{"missing code", &connecterrorspb.ErrorDef{Message: "oops"}, "has no code"},
+		{"invalid code", &connecterrorspb.ErrorDef{Code: "ERROR-X"}, "invalid error code"},
+		{"unknown connect code", &connecterrorspb.ErrorDef{Code: "ERROR_X", ConnectCode: 99}, "unknown connect_code 99"},
+		{"negative retry delay", &connecterrorspb.ErrorDef{Code: "ERROR_X", RetryDelay: durationpb.New(-time.Second)}, "must not be negative"},
+		{"invalid retry delay", &connecterrorspb.ErrorDef{Code: "ERROR_X", RetryDelay: &durationpb.Duration{Seconds: 1, Nanos: -1}}, "invalid retry_delay"},
+		{"unknown severity", &connecterrorspb.ErrorDef{Code: "ERROR_X", Severity: 7}, "unknown severity 7"},
+		{"relative help url", &connecterrorspb.ErrorDef{Code: "ERROR_X", HelpUrl: "/errors/x"}, "not an absolute URL"},
+		{"replaced_by without deprecated", &connecterrorspb.ErrorDef{Code: "ERROR_X", ReplacedBy: "ERROR_Y"}, "requires deprecated"},
+		{"invalid replaced_by", &connecterrorspb.ErrorDef{Code: "ERROR_X", Deprecated: true, ReplacedBy: "ERROR-Y"}, "invalid replaced_by"},
+		{"replaced by itself", &connecterrorspb.ErrorDef{Code: "ERROR_X", Deprecated: true, ReplacedBy: "ERROR_X"}, "invalid replaced_by"},
+		{"invalid locale", &connecterrorspb.ErrorDef{Code: "ERROR_X", LocalizedMessages: map[string]string{"de_DE!": "x"}}, "invalid locale"},
+		{"empty localized message", &connecterrorspb.ErrorDef{Code: "ERROR_X", LocalizedMessages: map[string]string{"de": ""}}, "empty localized message"},
+		{"undeclared localized field", &connecterrorspb.ErrorDef{Code: "ERROR_X", Message: "{{id}}", LocalizedMessages: map[string]string{"de": "{{name}}"}}, "uses {{name}}"},
+		{"undeclared sensitive field", &connecterrorspb.ErrorDef{Code: "ERROR_X", Message: "{{id}}", SensitiveFields: []string{"email"}}, `sensitive field "email"`},
+		{"conflicting param types", &connecterrorspb.ErrorDef{Code: "ERROR_X", Message: "{{n:int}} of {{n}} ({{n:decimal}})"}, `placeholder {{n}} has conflicting types "int" and "decimal"`},
+		{"duplicate sensitive field", &connecterrorspb.ErrorDef{Code: "ERROR_X", Message: "{{id}}", SensitiveFields: []string{"id", "id"}}, "duplicate sensitive field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &descriptorpb.MethodOptions{}
+			proto.SetExtension(opts, connecterrorspb.E_ConnectError, []*connecterrorspb.ErrorDef{tt.def})
+			if _, err := MethodErrors(opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("MethodErrors error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
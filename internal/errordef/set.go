@@ -0,0 +1,73 @@
+package errordef
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Set collects error definitions by code. Identical redefinitions are dropped
+// and conflicting ones are rejected, so that two declarations of the same code
+// can never silently shadow each other. The zero value is ready to use.
+type Set struct {
+	defs    []Def
+	index   map[string]int
+	sources []string
+}
+
+// Add adds d, declared at source (e.g. `user/v1/user.proto (rpc user.v1.UserService.GetUser)`).
+// It returns an error naming both sources if a definition with the same code
+// but different fields was added before.
+func (s *Set) Add(d Def, source string) error {
+	if i, ok := s.index[d.Code]; ok {
+		if field := diff(s.defs[i], d); field != "" {
+			return fmt.Errorf("conflicting definitions of %s in %s and %s: %s differs",
+				d.Code, s.sources[i], source, field)
+		}
+		return nil
+	}
+	if s.index == nil {
+		s.index = make(map[string]int)
+	}
+	s.index[d.Code] = len(s.defs)
+	s.defs = append(s.defs, d)
+	s.sources = append(s.sources, source)
+	return nil
+}
+
+// Defs returns the unique definitions in the order they were first added.
+func (s *Set) Defs() []Def {
+	return s.defs
+}
+
+// diff returns the name of the first field that differs between a and b,
+// or "" if they are identical.
+func diff(a, b Def) string {
+	switch {
+	case a.Message != b.Message:
+		return fmt.Sprintf("message (%q vs %q)", a.Message, b.Message)
+	case a.ConnectCode != b.ConnectCode:
+		return fmt.Sprintf("connect_code (%d vs %d)", a.ConnectCode, b.ConnectCode)
+	case a.Retryable != b.Retryable:
+		return fmt.Sprintf("retryable (%t vs %t)", a.Retryable, b.Retryable)
+	case a.Domain != b.Domain:
+		return fmt.Sprintf("domain (%q vs %q)", a.Domain, b.Domain)
+	case a.RetryDelay != b.RetryDelay:
+		return fmt.Sprintf("retry_delay (%s vs %s)", a.RetryDelay, b.RetryDelay)
+	case a.Description != b.Description:
+		return fmt.Sprintf("description (%q vs %q)", a.Description, b.Description)
+	case a.HelpURL != b.HelpURL:
+		return fmt.Sprintf("help_url (%q vs %q)", a.HelpURL, b.HelpURL)
+	case a.Severity != b.Severity:
+		return fmt.Sprintf("severity (%d vs %d)", a.Severity, b.Severity)
+	case a.Deprecated != b.Deprecated:
+		return fmt.Sprintf("deprecated (%t vs %t)", a.Deprecated, b.Deprecated)
+	case a.ReplacedBy != b.ReplacedBy:
+		return fmt.Sprintf("replaced_by (%q vs %q)", a.ReplacedBy, b.ReplacedBy)
+	case !maps.Equal(a.LocalizedMessages, b.LocalizedMessages):
+		return "localized_messages"
+	case !slices.Equal(a.SensitiveFields, b.SensitiveFields):
+		return fmt.Sprintf("sensitive_fields (%q vs %q)", a.SensitiveFields, b.SensitiveFields)
+	}
+	return ""
+}
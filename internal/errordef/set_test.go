@@ -0,0 +1,48 @@
+package errordef
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	var s Set
+	a := Def{Code: "ERROR_A", Message: "A", ConnectCode: 5}
+	if err := s.Add(a, "a.proto"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(a, "b.proto"); err != nil {
+		t.Errorf("identical redefinition rejected: %v", err)
+	}
+	if err := s.Add(Def{Code: "ERROR_B"}, "a.proto"); err != nil {
+		t.Fatal(err)
+	}
+	if defs := s.Defs(); len(defs) != 2 || defs[0].Code != "ERROR_A" || defs[1].Code != "ERROR_B" {
+		t.Errorf("Defs() = %v", defs)
+	}
+
+	conflicting := a
+	conflicting.RetryDelay = time.Second
+	err := s.Add(conflicting, "c.proto")
+	if err == nil {
+		t.Fatal("expected conflict error")
+	}
+	for _, want := range []string{"ERROR_A", "a.proto", "c.proto", "retry_delay"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	translated := a
+	translated.LocalizedMessages = map[string]string{"de": "A"}
+	if err := s.Add(translated, "d.proto"); err == nil || !strings.Contains(err.Error(), "localized_messages") {
+		t.Errorf("Add(translated) error = %v, want localized_messages conflict", err)
+	}
+
+	sensitive := a
+	sensitive.SensitiveFields = []string{"id"}
+	if err := s.Add(sensitive, "e.proto"); err == nil || !strings.Contains(err.Error(), "sensitive_fields") {
+		t.Errorf("Add(sensitive) error = %v, want sensitive_fields conflict", err)
+	}
+}
@@ -0,0 +1,101 @@
+package errordef
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a {{name}} or typed {{name:type}} placeholder in a message template.
+type Param struct {
+	Name string
+	Type string // empty for untyped placeholders
+}
+
+// Params parses {{name}} and {{name:type}} placeholders from a
+// message template. Returns unique params in order of first appearance; the
+// typed occurrences of a name determine its type (see checkParamTypes).
+func Params(message string) []Param {
+	var params []Param
+	for _, f := range Fields(message) {
+		params = append(params, Param{Name: f})
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	types := make(map[string]string, len(params))
+	for _, p := range typedPlaceholders(message) {
+		if _, seen := types[p.Name]; !seen {
+			types[p.Name] = p.Type
+		}
+	}
+	for j := range params {
+		params[j].Type = types[params[j].Name]
+	}
+	return params
+}
+
+// checkParamTypes reports an error if a placeholder of message is given two
+// different types, e.g. "{{n:int}} of {{n:decimal}}". Untyped occurrences of a
+// typed placeholder take its type.
+func checkParamTypes(message string) error {
+	types := make(map[string]string)
+	for _, p := range typedPlaceholders(message) {
+		if typ, seen := types[p.Name]; seen && typ != p.Type {
+			return fmt.Errorf("placeholder {{%s}} has conflicting types %q and %q", p.Name, typ, p.Type)
+		}
+		types[p.Name] = p.Type
+	}
+	return nil
+}
+
+// typedPlaceholders returns every {{name:type}} placeholder of message in
+// order of appearance, including repeated names.
+func typedPlaceholders(message string) []Param {
+	var typed []Param
+	i := 0
+	for i < len(message) {
+		start := strings.Index(message[i:], "{{")
+		if start == -1 {
+			break
+		}
+		start += i
+		end := strings.Index(message[start:], "}}")
+		if end == -1 {
+			break
+		}
+		end += start
+		if name, typ, ok := strings.Cut(message[start+2:end], ":"); ok {
+			typed = append(typed, Param{Name: name, Type: typ})
+		}
+		i = end + 2
+	}
+	return typed
+}
+
+// Fields parses {{placeholder}} names from a message template.
+// Type suffixes are dropped, so "{{amount:decimal}}" yields "amount".
+// Returns unique fields in order of first appearance.
+func Fields(message string) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	i := 0
+	for i < len(message) {
+		start := strings.Index(message[i:], "{{")
+		if start == -1 {
+			break
+		}
+		start += i
+		end := strings.Index(message[start:], "}}")
+		if end == -1 {
+			break
+		}
+		end += start
+		field, _, _ := strings.Cut(message[start+2:end], ":")
+		if field != "" && !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+		i = end + 2
+	}
+	return fields
+}
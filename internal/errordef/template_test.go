@@ -0,0 +1,50 @@
+package errordef
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"no placeholders", "Internal server error", nil},
+		{"single field", "User '{{id}}' not found", []string{"id"}},
+		{"multiple fields", "User '{{id}}' not found in '{{org}}'", []string{"id", "org"}},
+		{"duplicate fields", "{{id}} and {{id}} again", []string{"id"}},
+		{"snake_case field", "Product {{product_id}} unavailable", []string{"product_id"}},
+		{"empty message", "", nil},
+		{"adjacent placeholders", "{{a}}{{b}}", []string{"a", "b"}},
+		{"unclosed placeholder", "Hello {{name", nil},
+		{"three fields", "{{amount}} exceeds {{limit}} for {{account}}", []string{"amount", "limit", "account"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fields(tt.message)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fields(%q) = %v, want %v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	got := Params("{{amount:decimal}} over {{limit:int}} for {{account}} at {{at:timestamp}} ({{account}})")
+	want := []Param{
+		{Name: "amount", Type: "decimal"},
+		{Name: "limit", Type: "int"},
+		{Name: "account", Type: ""},
+		{Name: "at", Type: "timestamp"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Params = %v, want %v", got, want)
+	}
+
+	if got := Fields("{{amount:decimal}} {{id}}"); !reflect.DeepEqual(got, []string{"amount", "id"}) {
+		t.Errorf("Fields should drop type suffixes, got %v", got)
+	}
+}
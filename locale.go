@@ -0,0 +1,358 @@
+package connecterrors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// RegisterMessages adds message templates for locale to r, keyed by error code.
+// locale is a BCP 47 language tag such as "de" or "pt-BR"; it is matched
+// case-insensitively. Templates use the same {{placeholder}} syntax and data as
+// Error.MessageTpl. Registering a code again for the same locale replaces its
+// template. It is safe for concurrent use. Uses copy-on-write for lock-free reads.
+//
+// Example:
+//
+//	reg.RegisterMessages("de", map[cerr.ErrorCode]string{
+//	    cerr.ErrNotFound: "Ressource '{{id}}' nicht gefunden",
+//	})
+func (r *Registry) RegisterMessages(locale string, msgs map[ErrorCode]string) {
+	locale = canonicalLocale(locale)
+	if locale == "" || len(msgs) == 0 {
+		return
+	}
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	current := r.loadMessages()
+	updated := make(map[string]map[ErrorCode]string, len(current)+1)
+	for k, v := range current {
+		updated[k] = v
+	}
+	bundle := make(map[ErrorCode]string, len(current[locale])+len(msgs))
+	for code, tpl := range current[locale] {
+		bundle[code] = tpl
+	}
+	for code, tpl := range msgs {
+		bundle[code] = tpl
+	}
+	updated[locale] = bundle
+	r.messages.Store(updated)
+}
+
+// LoadMessages registers the message bundles in the root of fsys. Each bundle
+// is a JSON object mapping error codes to templates, named after its locale,
+// e.g. "de.json" or "pt-BR.json". Files without a .json extension are ignored.
+//
+// Example:
+//
+//	//go:embed locales/*.json
+//	var locales embed.FS
+//
+//	sub, _ := fs.Sub(locales, "locales")
+//	if err := cerr.LoadMessages(sub); err != nil { ... }
+func (r *Registry) LoadMessages(fsys fs.FS) error {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		locale := strings.TrimSuffix(path.Base(name), ".json")
+		if canonicalLocale(locale) == "" {
+			return fmt.Errorf("connecterrors: message bundle %s: invalid locale %q", name, locale)
+		}
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("connecterrors: message bundle %s: %w", name, err)
+		}
+		var msgs map[ErrorCode]string
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			return fmt.Errorf("connecterrors: message bundle %s: %w", name, err)
+		}
+		r.RegisterMessages(locale, msgs)
+	}
+	return nil
+}
+
+// LocalizedMessages returns the templates registered for code, keyed by locale.
+// Returns nil if code has no localized templates.
+func (r *Registry) LocalizedMessages(code ErrorCode) map[string]string {
+	var out map[string]string
+	for locale, bundle := range r.loadMessages() {
+		if tpl, ok := bundle[code]; ok {
+			if out == nil {
+				out = make(map[string]string)
+			}
+			out[locale] = tpl
+		}
+	}
+	return out
+}
+
+// Localize formats the template registered for code in the first of locales
+// that has one. A locale falls back to its parent tags, so "de-AT" matches a
+// "de" template. It returns the matched locale and formatted message, or
+// ok=false if none of locales has a template for code.
+//
+// Example:
+//
+//	locale, msg, ok := reg.Localize(cerr.ErrNotFound, cerr.M{"id": "42"}, "de-AT", "en")
+func (r *Registry) Localize(code ErrorCode, data M, locales ...string) (locale, message string, ok bool) {
+	locale, tpl, ok := r.localizedTemplate(code, locales)
+	if !ok {
+		return "", "", false
+	}
+	return locale, FormatTemplate(tpl, data), true
+}
+
+// localizedTemplate returns the template registered for code in the first of
+// locales that has one, and the locale it was found in.
+func (r *Registry) localizedTemplate(code ErrorCode, locales []string) (locale, tpl string, ok bool) {
+	bundles := r.loadMessages()
+	if len(bundles) == 0 {
+		return "", "", false
+	}
+	for _, l := range locales {
+		for tag := canonicalLocale(l); tag != ""; tag = parentLocale(tag) {
+			if tpl, found := bundles[tag][code]; found {
+				return tag, tpl, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// addLocalizedMessage attaches a google.rpc.LocalizedMessage detail for the
+// first of locales that has a template for code. It does nothing if no locale
+// matches or data lacks a placeholder of the localized template, so that raw
+// placeholders never reach clients. Sensitive fields of code are masked as in
+// the default message.
+func (r *Registry) addLocalizedMessage(connectErr *connect.Error, code ErrorCode, data M, locales []string) {
+	locale, tpl, ok := r.localizedTemplate(code, locales)
+	if !ok || ValidateTemplate(tpl, data) != nil {
+		return
+	}
+	if e, ok := r.Lookup(code); ok {
+		data = maskSensitive(data, e.SensitiveFields)
+	}
+	msg := FormatTemplate(tpl, data)
+	if detail, err := connect.NewErrorDetail(&errdetails.LocalizedMessage{Locale: locale, Message: msg}); err == nil {
+		connectErr.AddDetail(detail)
+	}
+}
+
+// loadMessages returns the current immutable message bundle snapshot.
+func (r *Registry) loadMessages() map[string]map[ErrorCode]string {
+	v := r.messages.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(map[string]map[ErrorCode]string)
+}
+
+// RegisterMessages adds message templates for locale to the default Registry.
+func RegisterMessages(locale string, msgs map[ErrorCode]string) {
+	defaultRegistry.RegisterMessages(locale, msgs)
+}
+
+// LoadMessages registers the message bundles in the root of fsys in the default Registry.
+func LoadMessages(fsys fs.FS) error { return defaultRegistry.LoadMessages(fsys) }
+
+// Localize formats the template registered for code in the default Registry
+// for the first matching locale.
+func Localize(code ErrorCode, data M, locales ...string) (locale, message string, ok bool) {
+	return defaultRegistry.Localize(code, data, locales...)
+}
+
+// localesKey is the context key for the caller's preferred locales.
+type localesKey struct{}
+
+// ContextWithLocales returns a copy of ctx carrying the caller's preferred
+// locales, most preferred first. LocaleInterceptor sets them from the
+// Accept-Language request header.
+func ContextWithLocales(ctx context.Context, locales ...string) context.Context {
+	return context.WithValue(ctx, localesKey{}, locales)
+}
+
+// LocalesFromContext returns the preferred locales stored in ctx, most
+// preferred first, or nil if there are none.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrNotFound, cerr.M{"id": id},
+//	    cerr.WithLocale(cerr.LocalesFromContext(ctx)...),
+//	)
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(localesKey{}).([]string)
+	return locales
+}
+
+// LocaleInterceptor is a server-side Connect interceptor that negotiates the
+// caller's locale from the Accept-Language request header and stores it in the
+// handler context (see LocalesFromContext). Locales already present in the
+// context are kept. When a handler returns a domain error without a
+// google.rpc.LocalizedMessage detail, one is attached for the first preferred
+// locale with a registered template. The error message itself stays in the
+// default language, so server-side logs are unaffected.
+//
+// Example:
+//
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(cerr.LocaleInterceptor()),
+//	))
+func LocaleInterceptor() connect.Interceptor {
+	return defaultRegistry.LocaleInterceptor()
+}
+
+// LocaleInterceptor returns a Connect interceptor that localizes domain errors
+// using the message bundles registered in r. See the package-level LocaleInterceptor.
+func (r *Registry) LocaleInterceptor() connect.Interceptor {
+	return &localeInterceptor{reg: r}
+}
+
+// localeInterceptor implements connect.Interceptor for LocaleInterceptor.
+type localeInterceptor struct {
+	reg *Registry
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *localeInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx = withRequestLocales(ctx, req.Header().Get("Accept-Language"))
+		resp, err := next(ctx, req)
+		if err != nil {
+			i.localize(ctx, err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *localeInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *localeInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx = withRequestLocales(ctx, conn.RequestHeader().Get("Accept-Language"))
+		err := next(ctx, conn)
+		if err != nil {
+			i.localize(ctx, err)
+		}
+		return err
+	}
+}
+
+// localize attaches a LocalizedMessage detail to err for the locales in ctx,
+// unless err is not a domain error or is already localized.
+func (i *localeInterceptor) localize(ctx context.Context, err error) {
+	locales := LocalesFromContext(ctx)
+	if len(locales) == 0 {
+		return
+	}
+	var connectErr *connect.Error
+	if !asConnectError(err, &connectErr) {
+		return
+	}
+	if _, ok := ExtractLocalizedMessage(connectErr); ok {
+		return
+	}
+	code, ok := ExtractErrorCode(connectErr)
+	if !ok {
+		return
+	}
+	i.reg.addLocalizedMessage(connectErr, ErrorCode(code), ErrorData(connectErr), locales)
+}
+
+// withRequestLocales stores the locales of an Accept-Language header in ctx
+// unless ctx already carries locales.
+func withRequestLocales(ctx context.Context, acceptLanguage string) context.Context {
+	if LocalesFromContext(ctx) != nil {
+		return ctx
+	}
+	if locales := parseAcceptLanguage(acceptLanguage); len(locales) > 0 {
+		return ContextWithLocales(ctx, locales...)
+	}
+	return ctx
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header
+// ordered by descending quality. Wildcards and tags with q=0 are dropped.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	locales := make([]string, len(tags))
+	for i, t := range tags {
+		locales[i] = t.tag
+	}
+	return locales
+}
+
+// canonicalLocale normalizes a BCP 47 language tag to its conventional case,
+// e.g. "PT_br" → "pt-BR" and "zh-hant" → "zh-Hant". It returns "" if locale
+// is not a well-formed tag.
+func canonicalLocale(locale string) string {
+	subtags := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	for i, s := range subtags {
+		if s == "" || len(s) > 8 || strings.TrimLeft(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+			return ""
+		}
+		s = strings.ToLower(s)
+		switch {
+		case i == 0:
+			if len(s) < 2 || strings.ContainsAny(s, "0123456789") {
+				return ""
+			}
+		case len(s) == 2:
+			s = strings.ToUpper(s)
+		case len(s) == 4:
+			s = strings.ToUpper(s[:1]) + s[1:]
+		}
+		subtags[i] = s
+	}
+	return strings.Join(subtags, "-")
+}
+
+// parentLocale returns locale without its last subtag, e.g. "zh-Hant-TW" → "zh-Hant",
+// or "" for a bare language.
+func parentLocale(locale string) string {
+	i := strings.LastIndexByte(locale, '-')
+	if i < 0 {
+		return ""
+	}
+	return locale[:i]
+}
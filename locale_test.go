@@ -0,0 +1,182 @@
+package connecterrors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func newLocaleRegistry() *connecterrors.Registry {
+	reg := connecterrors.NewRegistry()
+	reg.RegisterMessages("de", map[connecterrors.ErrorCode]string{
+		connecterrors.ErrNotFound: "Ressource '{{id}}' nicht gefunden",
+	})
+	reg.RegisterMessages("pt_br", map[connecterrors.ErrorCode]string{
+		connecterrors.ErrNotFound: "Recurso '{{id}}' não encontrado",
+	})
+	return reg
+}
+
+func TestLocalize(t *testing.T) {
+	reg := newLocaleRegistry()
+
+	tests := []struct {
+		locales    []string
+		wantLocale string
+		wantMsg    string
+	}{
+		{[]string{"de"}, "de", "Ressource '42' nicht gefunden"},
+		{[]string{"de-AT"}, "de", "Ressource '42' nicht gefunden"},
+		{[]string{"fr", "PT-BR"}, "pt-BR", "Recurso '42' não encontrado"},
+		{[]string{"pt"}, "", ""},
+		{nil, "", ""},
+	}
+	for _, tt := range tests {
+		locale, msg, ok := reg.Localize(connecterrors.ErrNotFound, connecterrors.M{"id": "42"}, tt.locales...)
+		if ok != (tt.wantLocale != "") || locale != tt.wantLocale || msg != tt.wantMsg {
+			t.Errorf("Localize(%v) = %q, %q, %t; want %q, %q", tt.locales, locale, msg, ok, tt.wantLocale, tt.wantMsg)
+		}
+	}
+
+	if _, _, ok := reg.Localize(connecterrors.ErrInternal, nil, "de"); ok {
+		t.Error("expected no localized message for a code without a template")
+	}
+	if got := reg.LocalizedMessages(connecterrors.ErrNotFound); len(got) != 2 || got["pt-BR"] == "" {
+		t.Errorf("LocalizedMessages = %v", got)
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	reg := newLocaleRegistry()
+	err := reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "42"}, connecterrors.WithLocale("de-DE"))
+
+	if err.Message() != "Resource '42' not found" {
+		t.Errorf("Message = %q, want default template", err.Message())
+	}
+	localized, ok := connecterrors.ExtractLocalizedMessage(err)
+	if !ok {
+		t.Fatal("expected LocalizedMessage detail")
+	}
+	if localized.Locale != "de" || localized.Message != "Ressource '42' nicht gefunden" {
+		t.Errorf("LocalizedMessage = %v", localized)
+	}
+
+	if _, ok := connecterrors.ExtractLocalizedMessage(reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "42"})); ok {
+		t.Error("expected no LocalizedMessage without WithLocale")
+	}
+}
+
+func TestWithLocaleCustomMessage(t *testing.T) {
+	reg := newLocaleRegistry()
+	reg.RegisterMessages("de", map[connecterrors.ErrorCode]string{
+		connecterrors.ErrInternal: "Interner Serverfehler",
+	})
+
+	err := reg.NewWithMessage(connecterrors.ErrNotFound, "No user {{id}}", connecterrors.M{"id": "42"}, connecterrors.WithLocale("de"))
+	if localized, ok := connecterrors.ExtractLocalizedMessage(err); !ok || localized.Message != "Ressource '42' nicht gefunden" {
+		t.Errorf("NewWithMessage LocalizedMessage = %v", localized)
+	}
+
+	err = reg.NewfWithOptions(connecterrors.ErrInternal, []connecterrors.Option{connecterrors.WithLocale("de")}, "shard %d down", 3)
+	if localized, ok := connecterrors.ExtractLocalizedMessage(err); !ok || localized.Message != "Interner Serverfehler" {
+		t.Errorf("NewfWithOptions LocalizedMessage = %v", localized)
+	}
+
+	// Newf has no template data for the {{id}} placeholder of the German template.
+	err = reg.NewfWithOptions(connecterrors.ErrNotFound, []connecterrors.Option{connecterrors.WithLocale("de")}, "user %s gone", "alice")
+	if localized, ok := connecterrors.ExtractLocalizedMessage(err); ok {
+		t.Errorf("unexpected LocalizedMessage %q with unset placeholders", localized.Message)
+	}
+}
+
+func TestLoadMessages(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	err := reg.LoadMessages(fstest.MapFS{
+		"fr.json":   {Data: []byte(`{"ERROR_NOT_FOUND": "Ressource '{{id}}' introuvable"}`)},
+		"README.md": {Data: []byte("ignored")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, msg, ok := reg.Localize(connecterrors.ErrNotFound, connecterrors.M{"id": "1"}, "fr-CA"); !ok || msg != "Ressource '1' introuvable" {
+		t.Errorf("Localize(fr-CA) = %q, %t", msg, ok)
+	}
+
+	if err := reg.LoadMessages(fstest.MapFS{"de.json": {Data: []byte(`not json`)}}); err == nil {
+		t.Error("expected error for malformed bundle")
+	}
+	if err := reg.LoadMessages(fstest.MapFS{"x!.json": {Data: []byte(`{}`)}}); err == nil {
+		t.Error("expected error for invalid locale")
+	}
+}
+
+func TestLocaleInterceptor(t *testing.T) {
+	reg := newLocaleRegistry()
+	var locales []string
+
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Get", connect.NewUnaryHandler(
+		"/test.v1.TestService/Get",
+		func(ctx context.Context, _ *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			locales = connecterrors.LocalesFromContext(ctx)
+			return nil, reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "42"})
+		},
+		connect.WithInterceptors(reg.LocaleInterceptor()),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+"/test.v1.TestService/Get")
+	req := connect.NewRequest(&emptypb.Empty{})
+	req.Header().Set("Accept-Language", "en;q=0.5, pt-BR, *;q=0.1, fr;q=0")
+
+	_, err := client.CallUnary(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(locales) != 2 || locales[0] != "pt-BR" || locales[1] != "en" {
+		t.Errorf("LocalesFromContext = %v, want [pt-BR en]", locales)
+	}
+	localized, ok := connecterrors.ExtractLocalizedMessage(err)
+	if !ok {
+		t.Fatal("expected LocalizedMessage detail on the client")
+	}
+	if localized.Locale != "pt-BR" || localized.Message != "Recurso '42' não encontrado" {
+		t.Errorf("LocalizedMessage = %v", localized)
+	}
+}
+
+func TestLocaleInterceptorKeepsExistingDetail(t *testing.T) {
+	reg := newLocaleRegistry()
+	handler := reg.LocaleInterceptor().WrapStreamingHandler(func(_ context.Context, _ connect.StreamingHandlerConn) error {
+		return reg.New(connecterrors.ErrNotFound, connecterrors.M{"id": "42"}, connecterrors.WithLocale("de"))
+	})
+
+	ctx := connecterrors.ContextWithLocales(context.Background(), "pt-BR")
+	err := handler(ctx, &fakeHandlerConn{})
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatal("expected *connect.Error")
+	}
+	var n int
+	for _, d := range connectErr.Details() {
+		if d.Type() == "google.rpc.LocalizedMessage" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("got %d LocalizedMessage details, want 1", n)
+	}
+	if localized, _ := connecterrors.ExtractLocalizedMessage(err); localized.GetLocale() != "de" {
+		t.Errorf("Locale = %q, want de", localized.GetLocale())
+	}
+}
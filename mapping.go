@@ -0,0 +1,228 @@
+package connecterrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// ErrorMapper returns the registered error code for err, reporting false if it
+// does not recognize err. It is registered with MapErrorFunc.
+type ErrorMapper func(err error) (ErrorCoder, bool)
+
+// defaultMappers map standard library errors to the built-in codes. They are
+// consulted after the mappers registered with MapError and MapErrorFunc.
+// io.EOF and io.ErrUnexpectedEOF are left out on purpose: a truncated request,
+// a dropped upstream connection and a corrupt file call for different codes,
+// so they fall back to ErrInternal unless mapped explicitly.
+var defaultMappers = []ErrorMapper{
+	mapTarget(context.DeadlineExceeded, ErrDeadlineExceeded),
+	mapTarget(os.ErrDeadlineExceeded, ErrDeadlineExceeded),
+	mapTarget(context.Canceled, ErrCanceled),
+	mapTarget(sql.ErrNoRows, ErrNotFound),
+	mapTarget(fs.ErrNotExist, ErrNotFound),
+	mapTarget(fs.ErrExist, ErrAlreadyExists),
+	mapTarget(fs.ErrPermission, ErrPermissionDenied),
+}
+
+// mapTarget returns an ErrorMapper that maps errors matching target with
+// errors.Is to code.
+func mapTarget(target error, code ErrorCoder) ErrorMapper {
+	return func(err error) (ErrorCoder, bool) {
+		if errors.Is(err, target) {
+			return code, true
+		}
+		return nil, false
+	}
+}
+
+// MapError maps errors that match target with errors.Is to code. Mappings are
+// consulted in registration order, before the built-in ones for context, sql,
+// io/fs and os errors. It is safe for concurrent use.
+//
+// Example:
+//
+//	cerr.MapError(redis.Nil, cerr.ErrNotFound)
+//	cerr.MapError(store.ErrConflict, userv1.ErrEmailExists)
+func (r *Registry) MapError(target error, code ErrorCoder) {
+	r.MapErrorFunc(mapTarget(target, code))
+}
+
+// MapErrorFunc adds fn to the error mappers of r, e.g. to match error types
+// with errors.As. See MapError for the order in which mappers are consulted.
+//
+// Example:
+//
+//	cerr.MapErrorFunc(func(err error) (cerr.ErrorCoder, bool) {
+//	    var netErr net.Error
+//	    if errors.As(err, &netErr) && netErr.Timeout() {
+//	        return cerr.ErrUnavailable, true
+//	    }
+//	    return nil, false
+//	})
+func (r *Registry) MapErrorFunc(fn ErrorMapper) {
+	if fn == nil {
+		return
+	}
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	current := r.loadMappers()
+	updated := make([]ErrorMapper, len(current), len(current)+1)
+	copy(updated, current)
+	r.mappers.Store(append(updated, fn))
+}
+
+// mapCode returns the registered error code that err maps to. Errors that
+// already carry a domain error code, as a *CodedError or an ErrorCode, keep it.
+// Unrecognized errors map to ErrInternal.
+func (r *Registry) mapCode(err error) ErrorCoder {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded
+	}
+	var code ErrorCode
+	if errors.As(err, &code) {
+		return code
+	}
+	for _, mappers := range [][]ErrorMapper{r.loadMappers(), defaultMappers} {
+		for _, fn := range mappers {
+			if code, ok := fn(err); ok {
+				return code
+			}
+		}
+	}
+	return ErrInternal
+}
+
+// From converts err into a domain error. The code is picked by the mappers
+// registered with MapError and MapErrorFunc, then by the built-in mappings
+// (context.DeadlineExceeded → ErrDeadlineExceeded, context.Canceled →
+// ErrCanceled, sql.ErrNoRows and fs.ErrNotExist → ErrNotFound, fs.ErrExist →
+// ErrAlreadyExists, fs.ErrPermission → ErrPermissionDenied), falling back to
+// ErrInternal. The message comes from the registered template and data; err is
+// kept as the cause and never sent to the client.
+//
+// io.EOF and io.ErrUnexpectedEOF are not mapped because the right code depends
+// on where they came from; map them with MapError where the meaning is clear,
+// e.g. cerr.MapError(io.ErrUnexpectedEOF, cerr.ErrInvalidArgument) for a
+// service that only reads them from request uploads.
+//
+// A *connect.Error is returned unchanged. Returns nil if err is nil.
+//
+// Example:
+//
+//	user, err := s.db.GetUser(ctx, id)
+//	if err != nil {
+//	    return nil, cerr.From(err, cerr.M{"id": id})
+//	}
+func From(err error, data M, opts ...Option) *connect.Error {
+	return defaultRegistry.From(err, data, opts...)
+}
+
+// From converts err into a domain error registered in r. See the package-level From.
+func (r *Registry) From(err error, data M, opts ...Option) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+	opts = append([]Option{WithCause(err)}, opts...)
+	return r.New(r.mapCode(err), data, opts...)
+}
+
+// fromWithoutData converts err like From for callers that have no template
+// data. Codes whose template has placeholders get a message derived from
+// their Connect code instead, e.g. "Not found", so that clients never see a
+// raw "{{id}}".
+func (r *Registry) fromWithoutData(err error) *connect.Error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+	code := r.mapCode(err)
+	e, ok := r.Lookup(ErrorCode(extractCode(code)))
+	if !ok || len(TemplateFields(e.MessageTpl)) == 0 {
+		return r.New(code, nil, WithCause(err))
+	}
+	msg := strings.ReplaceAll(e.ConnectCode.String(), "_", " ")
+	msg = strings.ToUpper(msg[:1]) + msg[1:]
+	return r.NewWithMessage(code, msg, nil, WithCause(err))
+}
+
+// loadMappers returns the current immutable mapper snapshot.
+func (r *Registry) loadMappers() []ErrorMapper {
+	v := r.mappers.Load()
+	if v == nil {
+		return nil
+	}
+	return v.([]ErrorMapper)
+}
+
+// MapError maps errors that match target to code in the default Registry.
+func MapError(target error, code ErrorCoder) { defaultRegistry.MapError(target, code) }
+
+// MapErrorFunc adds fn to the error mappers of the default Registry.
+func MapErrorFunc(fn ErrorMapper) { defaultRegistry.MapErrorFunc(fn) }
+
+// MapErrorInterceptor is a server-side Connect interceptor that converts
+// non-Connect errors returned by handlers with From, so that a handler may
+// return sql.ErrNoRows or context.DeadlineExceeded directly. Errors that are
+// already *connect.Error are passed through unchanged.
+//
+// The interceptor has no template data, so codes whose template has
+// placeholders get a generic message named after their Connect code, e.g.
+// "Not found" instead of "Resource '{{id}}' not found". Call From with data in
+// the handler to get the full message.
+//
+// Example:
+//
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(cerr.MapErrorInterceptor()),
+//	))
+func MapErrorInterceptor() connect.Interceptor {
+	return defaultRegistry.MapErrorInterceptor()
+}
+
+// MapErrorInterceptor returns a Connect interceptor that converts handler
+// errors with r.From. See the package-level MapErrorInterceptor.
+func (r *Registry) MapErrorInterceptor() connect.Interceptor {
+	return &mapErrorInterceptor{reg: r}
+}
+
+// mapErrorInterceptor implements connect.Interceptor for MapErrorInterceptor.
+type mapErrorInterceptor struct {
+	reg *Registry
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *mapErrorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil && !req.Spec().IsClient {
+			return resp, i.reg.fromWithoutData(err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *mapErrorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *mapErrorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := next(ctx, conn); err != nil {
+			return i.reg.fromWithoutData(err)
+		}
+		return nil
+	}
+}
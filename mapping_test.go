@@ -0,0 +1,144 @@
+package connecterrors_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// timeoutError is an error type matched with errors.As.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+
+func TestFromDefaultMappings(t *testing.T) {
+	tests := []struct {
+		err  error
+		want connecterrors.ErrorCode
+	}{
+		{context.DeadlineExceeded, connecterrors.ErrDeadlineExceeded},
+		{fmt.Errorf("query: %w", context.Canceled), connecterrors.ErrCanceled},
+		{fmt.Errorf("get user: %w", sql.ErrNoRows), connecterrors.ErrNotFound},
+		{&fs.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}, connecterrors.ErrNotFound},
+		{fs.ErrExist, connecterrors.ErrAlreadyExists},
+		{os.ErrPermission, connecterrors.ErrPermissionDenied},
+		{errors.New("boom"), connecterrors.ErrInternal},
+	}
+	for _, tt := range tests {
+		err := connecterrors.From(tt.err, connecterrors.M{"id": "42"})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("From(%v) = %v, want %q", tt.err, err, tt.want)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("From(%v) must keep the error as cause", tt.err)
+		}
+	}
+}
+
+func TestFromKeepsCauseServerSide(t *testing.T) {
+	err := connecterrors.From(fmt.Errorf("select * from users: %w", sql.ErrNoRows), connecterrors.M{"id": "42"})
+	if err.Message() != "Resource '42' not found" {
+		t.Errorf("Message = %q, want the template only", err.Message())
+	}
+	if connecterrors.From(nil, nil) != nil {
+		t.Error("From(nil) != nil")
+	}
+	connectErr := connect.NewError(connect.CodeAborted, errors.New("aborted"))
+	if got := connecterrors.From(fmt.Errorf("tx: %w", connectErr), nil); got != connectErr {
+		t.Errorf("From(*connect.Error) = %v, want it unchanged", got)
+	}
+	if got := connecterrors.From(connecterrors.ErrUnauthenticated, nil); !errors.Is(got, connecterrors.ErrUnauthenticated) {
+		t.Errorf("From(*CodedError) = %v, want ERROR_UNAUTHENTICATED", got)
+	}
+}
+
+func TestMapError(t *testing.T) {
+	errDuplicate := errors.New("duplicate key")
+	reg := connecterrors.NewRegistry()
+	reg.MapError(errDuplicate, connecterrors.ErrAlreadyExists)
+	reg.MapError(sql.ErrNoRows, connecterrors.ErrUnauthenticated)
+	reg.MapErrorFunc(func(err error) (connecterrors.ErrorCoder, bool) {
+		var timeout timeoutError
+		return connecterrors.ErrUnavailable, errors.As(err, &timeout)
+	})
+
+	if err := reg.From(fmt.Errorf("insert: %w", errDuplicate), nil); !errors.Is(err, connecterrors.ErrAlreadyExists) {
+		t.Errorf("From = %v, want ERROR_ALREADY_EXISTS", err)
+	}
+	if err := reg.From(sql.ErrNoRows, nil); !errors.Is(err, connecterrors.ErrUnauthenticated) {
+		t.Errorf("custom mapping must take precedence, got %v", err)
+	}
+	if err := reg.From(fmt.Errorf("dial: %w", timeoutError{}), nil); !errors.Is(err, connecterrors.ErrUnavailable) {
+		t.Errorf("From = %v, want ERROR_UNAVAILABLE", err)
+	}
+	if err := connecterrors.From(errDuplicate, nil); !errors.Is(err, connecterrors.ErrInternal) {
+		t.Errorf("mappings must not leak into the default registry, got %v", err)
+	}
+}
+
+func TestMapErrorInterceptor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Get", connect.NewUnaryHandler(
+		"/test.v1.TestService/Get",
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			return nil, fmt.Errorf("get user: %w", sql.ErrNoRows)
+		},
+		connect.WithInterceptors(connecterrors.MapErrorInterceptor()),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+"/test.v1.TestService/Get")
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("CodeOf = %v, want not_found", connect.CodeOf(err))
+	}
+	if !connecterrors.MatchError(err, "", connecterrors.ErrNotFound) {
+		t.Errorf("expected ERROR_NOT_FOUND, got %v", err)
+	}
+	// The template needs {{id}}, which the interceptor cannot know.
+	if msg := connectMessage(t, err); msg != "Not found" {
+		t.Errorf("client message = %q, want %q", msg, "Not found")
+	}
+
+	handler := connecterrors.MapErrorInterceptor().WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return os.ErrPermission
+	})
+	err = handler(context.Background(), &fakeHandlerConn{})
+	if !errors.Is(err, connecterrors.ErrPermissionDenied) {
+		t.Errorf("streaming error = %v, want ERROR_PERMISSION_DENIED", err)
+	}
+	if msg := connectMessage(t, err); msg != "Permission denied" {
+		t.Errorf("streaming message = %q, want %q", msg, "Permission denied")
+	}
+
+	// Templates without placeholders are kept.
+	handler = connecterrors.MapErrorInterceptor().WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return context.DeadlineExceeded
+	})
+	if msg := connectMessage(t, handler(context.Background(), &fakeHandlerConn{})); msg != "Request timed out" {
+		t.Errorf("deadline message = %q, want %q", msg, "Request timed out")
+	}
+}
+
+// connectMessage returns the message of the *connect.Error in err.
+func connectMessage(t *testing.T, err error) string {
+	t.Helper()
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected *connect.Error, got %v", err)
+	}
+	return connectErr.Message()
+}
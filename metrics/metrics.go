@@ -0,0 +1,230 @@
+// Package metrics exports Prometheus metrics for Connect RPC errors keyed by
+// registered error code.
+//
+// Error codes that are not found in the Registry are reported as
+// "unregistered", so arbitrary codes sent by a misbehaving peer cannot blow up
+// label cardinality.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// Unregistered is the code label of errors without a registered error code.
+const Unregistered = "unregistered"
+
+// Label names of the exported metrics.
+const (
+	LabelProcedure   = "procedure"
+	LabelCode        = "code"
+	LabelConnectCode = "connect_code"
+	LabelRetryable   = "retryable"
+)
+
+// Option configures New.
+type Option func(*config)
+
+// config holds the settings collected from Option values.
+type config struct {
+	registry  *connecterrors.Registry
+	namespace string
+	buckets   []float64
+}
+
+// WithRegistry resolves error codes against r instead of the default Registry.
+func WithRegistry(r *connecterrors.Registry) Option {
+	return func(c *config) {
+		c.registry = r
+	}
+}
+
+// WithNamespace sets the Prometheus namespace prefixed to the metric names
+// (default "connect").
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithLatency enables the <namespace>_error_duration_seconds histogram of the
+// duration of failed calls. A nil buckets uses prometheus.DefBuckets.
+func WithLatency(buckets []float64) Option {
+	return func(c *config) {
+		if buckets == nil {
+			buckets = prometheus.DefBuckets
+		}
+		c.buckets = buckets
+	}
+}
+
+// Metrics is a prometheus.Collector counting Connect RPC errors. Register it
+// with a prometheus.Registerer and install its Interceptor.
+//
+// It exports:
+//
+//   - <namespace>_errors_total{procedure, code, connect_code, retryable}
+//   - <namespace>_error_duration_seconds{procedure, code, connect_code}, if WithLatency is set
+type Metrics struct {
+	registry *connecterrors.Registry
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// New returns error metrics configured by opts.
+//
+// Example:
+//
+//	m := metrics.New(metrics.WithLatency(nil))
+//	prometheus.MustRegister(m)
+//
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(m.Interceptor()),
+//	))
+func New(opts ...Option) *Metrics {
+	c := config{registry: connecterrors.DefaultRegistry(), namespace: "connect"}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	m := &Metrics{
+		registry: c.registry,
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Name:      "errors_total",
+			Help:      "Number of RPCs that failed, by procedure and error code.",
+		}, []string{LabelProcedure, LabelCode, LabelConnectCode, LabelRetryable}),
+	}
+	if c.buckets != nil {
+		m.latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Name:      "error_duration_seconds",
+			Help:      "Duration of RPCs that failed, by procedure and error code.",
+			Buckets:   c.buckets,
+		}, []string{LabelProcedure, LabelCode, LabelConnectCode})
+	}
+	return m
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.errors.Describe(ch)
+	if m.latency != nil {
+		m.latency.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.errors.Collect(ch)
+	if m.latency != nil {
+		m.latency.Collect(ch)
+	}
+}
+
+// Interceptor returns a Connect interceptor that records the errors of unary
+// and streaming calls, on both clients and handlers. A streaming call is
+// counted once, for the first error it fails with.
+func (m *Metrics) Interceptor() connect.Interceptor {
+	return &interceptor{metrics: m}
+}
+
+// Observe records a failed call of procedure that started at start. It is
+// called by the Interceptor and may be used to record errors observed elsewhere.
+// It does nothing if err is nil.
+func (m *Metrics) Observe(procedure string, err error, start time.Time) {
+	if err == nil {
+		return
+	}
+	code, retryable := Unregistered, false
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		if def, ok := m.registry.FromError(connectErr); ok {
+			code, retryable = string(def.Code), def.Retryable
+		}
+	}
+	connectCode := connect.CodeOf(err).String()
+	m.errors.WithLabelValues(procedure, code, connectCode, strconv.FormatBool(retryable)).Inc()
+	if m.latency != nil {
+		m.latency.WithLabelValues(procedure, code, connectCode).Observe(time.Since(start).Seconds())
+	}
+}
+
+// interceptor implements connect.Interceptor for Metrics.Interceptor.
+type interceptor struct {
+	metrics *Metrics
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		i.metrics.Observe(req.Spec().Procedure, err, start)
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &clientConn{
+			StreamingClientConn: next(ctx, spec),
+			metrics:             i.metrics,
+			start:               time.Now(),
+		}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		i.metrics.Observe(conn.Spec().Procedure, err, start)
+		return err
+	}
+}
+
+// clientConn wraps a client-side stream to record the first error it fails with.
+type clientConn struct {
+	connect.StreamingClientConn
+	metrics *Metrics
+	start   time.Time
+	once    sync.Once
+}
+
+// Receive implements connect.StreamingClientConn.
+func (c *clientConn) Receive(msg any) error {
+	return c.observe(c.StreamingClientConn.Receive(msg))
+}
+
+// Send implements connect.StreamingClientConn.
+func (c *clientConn) Send(msg any) error {
+	return c.observe(c.StreamingClientConn.Send(msg))
+}
+
+// CloseResponse implements connect.StreamingClientConn.
+func (c *clientConn) CloseResponse() error {
+	return c.observe(c.StreamingClientConn.CloseResponse())
+}
+
+// observe records err unless it is nil or io.EOF, which ends a stream normally.
+func (c *clientConn) observe(err error) error {
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.once.Do(func() {
+			c.metrics.Observe(c.Spec().Procedure, err, c.start)
+		})
+	}
+	return err
+}
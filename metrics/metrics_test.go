@@ -0,0 +1,103 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+	"github.com/balcieren/connect-errors-go/metrics"
+)
+
+// streamConn is a minimal connect.StreamingHandlerConn for tests.
+type streamConn struct {
+	connect.StreamingHandlerConn
+}
+
+func (streamConn) Spec() connect.Spec {
+	return connect.Spec{Procedure: "/test.v1.TestService/Watch"}
+}
+
+// callUnary runs a unary call through the interceptor of m that fails with err.
+func callUnary(m *metrics.Metrics, err error) {
+	handler := m.Interceptor().WrapUnary(func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, err
+	})
+	_, _ = handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+}
+
+func TestErrorsTotal(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	m := metrics.New(metrics.WithRegistry(reg))
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(m)
+
+	unknown := connect.NewError(connect.CodeNotFound, errors.New("nope"))
+	unknown.Meta().Set("x-error-code", "ERROR_FROM_A_MISBEHAVING_PEER")
+
+	callUnary(m, reg.New(connecterrors.ErrUnavailable, nil))
+	callUnary(m, reg.New(connecterrors.ErrUnavailable, nil))
+	callUnary(m, unknown)
+	callUnary(m, errors.New("boom"))
+	callUnary(m, nil)
+
+	want := `
+# HELP connect_errors_total Number of RPCs that failed, by procedure and error code.
+# TYPE connect_errors_total counter
+connect_errors_total{code="ERROR_UNAVAILABLE",connect_code="unavailable",procedure="",retryable="true"} 2
+connect_errors_total{code="unregistered",connect_code="not_found",procedure="",retryable="false"} 1
+connect_errors_total{code="unregistered",connect_code="unknown",procedure="",retryable="false"} 1
+`
+	if err := testutil.GatherAndCompare(promReg, strings.NewReader(want), "connect_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(m, "connect_error_duration_seconds"); n != 0 {
+		t.Errorf("got %d latency series without WithLatency, want 0", n)
+	}
+}
+
+func TestLatencyStreaming(t *testing.T) {
+	m := metrics.New(metrics.WithNamespace("api"), metrics.WithLatency(nil))
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(m)
+
+	handler := m.Interceptor().WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return connecterrors.New(connecterrors.ErrNotFound, connecterrors.M{"id": "1"})
+	})
+	_ = handler(context.Background(), streamConn{})
+
+	families, err := promReg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, mf := range families {
+		if mf.GetName() != "api_error_duration_seconds" {
+			continue
+		}
+		found = true
+		series := mf.GetMetric()
+		if len(series) != 1 || series[0].GetHistogram().GetSampleCount() != 1 {
+			t.Fatalf("api_error_duration_seconds = %v, want one observation", series)
+		}
+		labels := map[string]string{}
+		for _, l := range series[0].GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["procedure"] != "/test.v1.TestService/Watch" || labels["code"] != "ERROR_NOT_FOUND" || labels["connect_code"] != "not_found" {
+			t.Errorf("labels = %v", labels)
+		}
+	}
+	if !found {
+		t.Error("api_error_duration_seconds not exported")
+	}
+	if n := testutil.CollectAndCount(m, "api_errors_total"); n != 1 {
+		t.Errorf("got %d api_errors_total series, want 1", n)
+	}
+}
@@ -0,0 +1,207 @@
+package connecterrors
+
+import (
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// Option configures a single error construction call, overriding the
+// registered Error definition for that call only. Options are accepted by
+// New, NewWithMessage, Wrap, NewfWithOptions and the generated NewErrXxx
+// constructors.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrResourceExhausted, cerr.M{"reason": "rate limit"},
+//	    cerr.WithRetryDelay(limiter.Reset()),
+//	    cerr.WithMeta("x-ratelimit-limit", "100"),
+//	)
+type Option func(*options)
+
+// options holds the per-call overrides collected from Option values.
+type options struct {
+	retryDelay    time.Duration
+	hasRetryDelay bool
+
+	connectCode    connect.Code
+	hasConnectCode bool
+
+	details []*connect.ErrorDetail
+	meta    http.Header
+	cause   error
+	locales []string
+
+	internalMsg string
+
+	quotaViolations        []*errdetails.QuotaFailure_Violation
+	preconditionViolations []*errdetails.PreconditionFailure_Violation
+}
+
+// WithRetryDelay overrides the registered RetryDelay for this call.
+// The delay is sent in the google.rpc.RetryInfo detail and mirrored into the
+// Retry-After metadata header. It only takes effect for retryable errors.
+func WithRetryDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.retryDelay = d
+		o.hasRetryDelay = true
+	}
+}
+
+// WithConnectCode overrides the registered Connect status code for this call.
+func WithConnectCode(code connect.Code) Option {
+	return func(o *options) {
+		o.connectCode = code
+		o.hasConnectCode = true
+	}
+}
+
+// WithErrorDetails attaches additional protobuf details to the error.
+// Nil details are ignored.
+func WithErrorDetails(details ...*connect.ErrorDetail) Option {
+	return func(o *options) {
+		for _, d := range details {
+			if d != nil {
+				o.details = append(o.details, d)
+			}
+		}
+	}
+}
+
+// WithMeta sets an additional metadata header on the error.
+// Headers set this way are applied after the error code and retryable headers.
+func WithMeta(key, value string) Option {
+	return func(o *options) {
+		if o.meta == nil {
+			o.meta = make(http.Header)
+		}
+		o.meta.Set(key, value)
+	}
+}
+
+// WithCause records err as the underlying cause without changing the message.
+// The cause is reachable through errors.Is and errors.As.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrNotFound, cerr.M{"id": id}, cerr.WithCause(sql.ErrNoRows))
+func WithCause(err error) Option {
+	return func(o *options) {
+		o.cause = err
+	}
+}
+
+// WithInternalMessage records a developer-only message, returned by
+// CodedError.InternalMessage. Unlike the template message it is not sent to
+// clients, except in the google.rpc.DebugInfo detail of DebugInfoInterceptor.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrUnavailable, nil,
+//	    cerr.WithInternalMessage("inventory service returned 503 for sku "+sku),
+//	)
+func WithInternalMessage(msg string) Option {
+	return func(o *options) {
+		o.internalMsg = msg
+	}
+}
+
+// WithQuotaViolation adds a violation to the google.rpc.QuotaFailure detail of
+// the error. subject identifies the exhausted quota, e.g. "project:123" or
+// "user:42"; description explains which limit was hit. All quota violations of a
+// call share one detail.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrResourceExhausted, cerr.M{"reason": "daily upload limit"},
+//	    cerr.WithQuotaViolation("user:"+userID, "Daily limit of 100 uploads exceeded"),
+//	)
+func WithQuotaViolation(subject, description string) Option {
+	return func(o *options) {
+		o.quotaViolations = append(o.quotaViolations, &errdetails.QuotaFailure_Violation{
+			Subject:     subject,
+			Description: description,
+		})
+	}
+}
+
+// WithPreconditionViolation adds a violation to the google.rpc.PreconditionFailure
+// detail of the error. typ is a service-specific category such as "TOS",
+// subject names the failing entity, e.g. "google.com/cloud", and description
+// explains how to fix it. All precondition violations of a call share one detail.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrFailedPrecondition, cerr.M{"reason": "terms not accepted"},
+//	    cerr.WithPreconditionViolation("TOS", "user:"+userID, "Accept the terms of service first"),
+//	)
+func WithPreconditionViolation(typ, subject, description string) Option {
+	return func(o *options) {
+		o.preconditionViolations = append(o.preconditionViolations, &errdetails.PreconditionFailure_Violation{
+			Type:        typ,
+			Subject:     subject,
+			Description: description,
+		})
+	}
+}
+
+// WithLocale attaches a google.rpc.LocalizedMessage detail for the first of
+// locales with a template registered via RegisterMessages. The error message
+// itself is unchanged. The localized template is formatted with the template
+// data of the call; it is skipped if the data lacks one of its placeholders,
+// as with NewfWithOptions, which has no template data.
+//
+// Example:
+//
+//	return nil, cerr.New(cerr.ErrNotFound, cerr.M{"id": id},
+//	    cerr.WithLocale(cerr.LocalesFromContext(ctx)...),
+//	)
+func WithLocale(locales ...string) Option {
+	return func(o *options) {
+		o.locales = append(o.locales, locales...)
+	}
+}
+
+// newOptions collects opts into an options value.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// apply returns a copy of e with the per-call definition overrides applied.
+func (o *options) apply(e Error) Error {
+	if o.hasRetryDelay {
+		e.RetryDelay = o.retryDelay
+	}
+	if o.hasConnectCode {
+		e.ConnectCode = o.connectCode
+	}
+	return e
+}
+
+// finish attaches the per-call details and metadata to connectErr.
+func (o *options) finish(connectErr *connect.Error) {
+	if len(o.quotaViolations) > 0 {
+		if d, err := connect.NewErrorDetail(&errdetails.QuotaFailure{Violations: o.quotaViolations}); err == nil {
+			connectErr.AddDetail(d)
+		}
+	}
+	if len(o.preconditionViolations) > 0 {
+		if d, err := connect.NewErrorDetail(&errdetails.PreconditionFailure{Violations: o.preconditionViolations}); err == nil {
+			connectErr.AddDetail(d)
+		}
+	}
+	for _, d := range o.details {
+		connectErr.AddDetail(d)
+	}
+	for k, v := range o.meta {
+		connectErr.Meta()[k] = append([]string(nil), v...)
+	}
+}
@@ -0,0 +1,108 @@
+// Package otel records domain errors on OpenTelemetry spans. Its interceptor
+// adds the registered error code, Connect code, retryable flag and ErrorInfo
+// domain as span attributes, sets the span status and adds an exception event.
+//
+// The interceptor does not start spans. It annotates the span already in the
+// context, e.g. one started by otelconnect or an HTTP middleware, so it must be
+// listed after the interceptor that starts the span.
+package otel
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// Span attribute keys set by RecordError.
+const (
+	// ErrorCodeKey is the registered error code, e.g. "ERROR_NOT_FOUND".
+	ErrorCodeKey = attribute.Key("connect_errors.code")
+
+	// ConnectCodeKey is the Connect status code, e.g. "not_found".
+	ConnectCodeKey = attribute.Key("rpc.connect_rpc.error_code")
+
+	// RetryableKey reports whether the error is retryable.
+	RetryableKey = attribute.Key("connect_errors.retryable")
+
+	// DomainKey is the google.rpc.ErrorInfo domain of the error.
+	DomainKey = attribute.Key("connect_errors.domain")
+
+	// errorTypeKey and the exception keys follow the OpenTelemetry semantic conventions.
+	errorTypeKey        = attribute.Key("error.type")
+	exceptionTypeKey    = attribute.Key("exception.type")
+	exceptionMessageKey = attribute.Key("exception.message")
+)
+
+// Option configures NewInterceptor.
+type Option func(*config)
+
+// config holds the settings collected from Option values.
+type config struct {
+	registry *connecterrors.Registry
+}
+
+// WithRegistry resolves error codes against r instead of the default Registry.
+func WithRegistry(r *connecterrors.Registry) Option {
+	return func(c *config) {
+		c.registry = r
+	}
+}
+
+// NewInterceptor returns a Connect interceptor that records every domain error
+// of unary and streaming calls, on both clients and handlers, on the span in
+// the call's context with RecordError.
+//
+// Example:
+//
+//	otelInterceptor, _ := otelconnect.NewInterceptor()
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(otelInterceptor, cerrotel.NewInterceptor()),
+//	))
+func NewInterceptor(opts ...Option) connect.Interceptor {
+	c := config{registry: connecterrors.DefaultRegistry()}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	return c.registry.ErrorInterceptor(func(ctx context.Context, connectErr *connect.Error, def connecterrors.Error) {
+		RecordError(trace.SpanFromContext(ctx), connectErr, def)
+	})
+}
+
+// RecordError records a domain error on span: it sets the ErrorCodeKey,
+// ConnectCodeKey, RetryableKey and, if the error carries an ErrorInfo detail,
+// DomainKey attributes, sets the span status to Error with the formatted
+// message and adds an exception event. It does nothing if span is not recording.
+//
+// Use it directly to combine tracing with other work in one ErrorInterceptor:
+//
+//	cerr.ErrorInterceptor(func(ctx context.Context, err *connect.Error, def cerr.Error) {
+//	    cerrotel.RecordError(trace.SpanFromContext(ctx), err, def)
+//	    slog.ErrorContext(ctx, "rpc error", "code", def.Code)
+//	})
+func RecordError(span trace.Span, connectErr *connect.Error, def connecterrors.Error) {
+	if !span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		ErrorCodeKey.String(string(def.Code)),
+		ConnectCodeKey.String(connectErr.Code().String()),
+		RetryableKey.Bool(def.Retryable),
+		errorTypeKey.String(string(def.Code)),
+	}
+	if info, ok := connecterrors.ExtractErrorInfo(connectErr); ok && info.GetDomain() != "" {
+		attrs = append(attrs, DomainKey.String(info.GetDomain()))
+	}
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Error, connectErr.Message())
+	span.AddEvent("exception", trace.WithAttributes(
+		exceptionTypeKey.String(string(def.Code)),
+		exceptionMessageKey.String(connectErr.Message()),
+	))
+}
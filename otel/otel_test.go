@@ -0,0 +1,134 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+	cerrotel "github.com/balcieren/connect-errors-go/otel"
+)
+
+// streamConn is a minimal connect.StreamingHandlerConn for tests.
+type streamConn struct {
+	connect.StreamingHandlerConn
+}
+
+func (streamConn) Send(any) error { return nil }
+
+// startSpan starts a span recorded by a new in-memory exporter.
+func startSpan(t *testing.T) (context.Context, trace.Span, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("test").Start(context.Background(), "rpc")
+	return ctx, span, exporter
+}
+
+// attrs returns kvs keyed by attribute name.
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestInterceptorUnary(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.SetDomain("users.example.com")
+	reg.Register(connecterrors.Error{
+		Code:        "ERROR_USER_LOCKED",
+		MessageTpl:  "User '{{id}}' is locked",
+		ConnectCode: connect.CodeFailedPrecondition,
+		Retryable:   true,
+	})
+
+	ctx, span, exporter := startSpan(t)
+	handler := cerrotel.NewInterceptor(cerrotel.WithRegistry(reg)).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, reg.New(connecterrors.ErrorCode("ERROR_USER_LOCKED"), connecterrors.M{"id": "42"})
+		},
+	)
+	if _, err := handler(ctx, connect.NewRequest(&emptypb.Empty{})); err == nil {
+		t.Fatal("expected error")
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	a := attrs(got.Attributes)
+	if a[cerrotel.ErrorCodeKey].AsString() != "ERROR_USER_LOCKED" {
+		t.Errorf("%s = %v", cerrotel.ErrorCodeKey, a[cerrotel.ErrorCodeKey])
+	}
+	if a[cerrotel.ConnectCodeKey].AsString() != "failed_precondition" {
+		t.Errorf("%s = %v", cerrotel.ConnectCodeKey, a[cerrotel.ConnectCodeKey])
+	}
+	if !a[cerrotel.RetryableKey].AsBool() {
+		t.Errorf("%s = %v, want true", cerrotel.RetryableKey, a[cerrotel.RetryableKey])
+	}
+	if a[cerrotel.DomainKey].AsString() != "users.example.com" {
+		t.Errorf("%s = %v", cerrotel.DomainKey, a[cerrotel.DomainKey])
+	}
+	if got.Status.Code != codes.Error || got.Status.Description != "User '42' is locked" {
+		t.Errorf("Status = %+v", got.Status)
+	}
+	if len(got.Events) != 1 || got.Events[0].Name != "exception" {
+		t.Fatalf("Events = %+v, want one exception event", got.Events)
+	}
+	if msg := attrs(got.Events[0].Attributes)["exception.message"].AsString(); msg != "User '42' is locked" {
+		t.Errorf("exception.message = %q", msg)
+	}
+}
+
+func TestInterceptorStreaming(t *testing.T) {
+	ctx, span, exporter := startSpan(t)
+	handler := cerrotel.NewInterceptor().WrapStreamingHandler(
+		func(context.Context, connect.StreamingHandlerConn) error {
+			return connecterrors.New(connecterrors.ErrUnavailable, nil)
+		},
+	)
+	_ = handler(ctx, streamConn{})
+	span.End()
+
+	got := exporter.GetSpans()[0]
+	if a := attrs(got.Attributes); a[cerrotel.ErrorCodeKey].AsString() != "ERROR_UNAVAILABLE" {
+		t.Errorf("%s = %v", cerrotel.ErrorCodeKey, a[cerrotel.ErrorCodeKey])
+	}
+	if got.Status.Code != codes.Error {
+		t.Errorf("Status = %+v", got.Status)
+	}
+}
+
+func TestInterceptorIgnoresPlainErrors(t *testing.T) {
+	ctx, span, exporter := startSpan(t)
+	handler := cerrotel.NewInterceptor().WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, connect.NewError(connect.CodeInternal, nil)
+		},
+	)
+	_, _ = handler(ctx, connect.NewRequest(&emptypb.Empty{}))
+	span.End()
+
+	got := exporter.GetSpans()[0]
+	if len(got.Attributes) != 0 || len(got.Events) != 0 || got.Status.Code != codes.Unset {
+		t.Errorf("expected span untouched, got %+v", got)
+	}
+}
+
+func TestRecordErrorNonRecording(t *testing.T) {
+	// Must not panic for the no-op span of a context without a span.
+	cerrotel.RecordError(trace.SpanFromContext(context.Background()),
+		connecterrors.New(connecterrors.ErrInternal, nil), connecterrors.MustLookup(connecterrors.ErrInternal))
+}
@@ -0,0 +1,81 @@
+package connecterrors
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Placeholder types for typed template parameters, written as {{name:type}}.
+// protoc-gen-connect-errors-go maps them to Go field types in the generated
+// params structs and converts values with the FormatXxx/ParseXxx helpers.
+//
+// Example:
+//
+//	"Insufficient funds: requested {{amount:decimal}}, available {{balance:decimal}}"
+const (
+	ParamString    = "string"    // string (default when no type is given)
+	ParamInt       = "int"       // int64
+	ParamDecimal   = "decimal"   // string holding a decimal number, e.g. "12.50"
+	ParamBool      = "bool"      // bool
+	ParamTimestamp = "timestamp" // time.Time
+	ParamDuration  = "duration"  // time.Duration
+)
+
+// FormatInt formats an int template parameter.
+func FormatInt(v int64) string { return strconv.FormatInt(v, 10) }
+
+// FormatBool formats a bool template parameter as "true" or "false".
+func FormatBool(v bool) string { return strconv.FormatBool(v) }
+
+// FormatTimestamp formats a timestamp template parameter as RFC 3339 in UTC.
+func FormatTimestamp(v time.Time) string { return v.UTC().Format(time.RFC3339Nano) }
+
+// FormatDuration formats a duration template parameter, e.g. "1m30s".
+func FormatDuration(v time.Duration) string { return v.String() }
+
+// ParseInt parses an int template parameter.
+func ParseInt(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// ParseDecimal validates a decimal template parameter and returns it
+// unchanged. Decimals are kept as strings so that their scale survives, e.g.
+// "12.50" stays "12.50". A decimal is an optional sign followed by digits with
+// an optional fractional part, e.g. "-3", "0.25" or "1000.00".
+func ParseDecimal(s string) (string, error) {
+	digits := s
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+	seenDigit, seenPoint := false, false
+	for i := 0; i < len(digits); i++ {
+		switch c := digits[i]; {
+		case c >= '0' && c <= '9':
+			seenDigit = true
+		case c == '.' && !seenPoint && seenDigit && i < len(digits)-1:
+			seenPoint = true
+		default:
+			return "", fmt.Errorf("connecterrors: invalid decimal %q", s)
+		}
+	}
+	if !seenDigit {
+		return "", fmt.Errorf("connecterrors: invalid decimal %q", s)
+	}
+	return s, nil
+}
+
+// ParseBool parses a bool template parameter.
+func ParseBool(s string) (bool, error) {
+	return strconv.ParseBool(s)
+}
+
+// ParseTimestamp parses an RFC 3339 timestamp template parameter.
+func ParseTimestamp(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// ParseDuration parses a duration template parameter.
+func ParseDuration(s string) (time.Duration, error) {
+	return time.ParseDuration(s)
+}
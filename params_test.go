@@ -0,0 +1,89 @@
+package connecterrors_test
+
+import (
+	"testing"
+	"time"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func TestParamRoundTrip(t *testing.T) {
+	if got, err := connecterrors.ParseInt(connecterrors.FormatInt(-42)); err != nil || got != -42 {
+		t.Errorf("int round trip = %d, %v", got, err)
+	}
+	if got, err := connecterrors.ParseBool(connecterrors.FormatBool(true)); err != nil || !got {
+		t.Errorf("bool round trip = %t, %v", got, err)
+	}
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 6, time.FixedZone("X", 3600))
+	if got, err := connecterrors.ParseTimestamp(connecterrors.FormatTimestamp(ts)); err != nil || !got.Equal(ts) {
+		t.Errorf("timestamp round trip = %v, %v; want %v", got, err, ts)
+	}
+	if got, err := connecterrors.ParseDuration(connecterrors.FormatDuration(90 * time.Second)); err != nil || got != 90*time.Second {
+		t.Errorf("duration round trip = %v, %v", got, err)
+	}
+}
+
+func TestFormatParams(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"timestamp utc", connecterrors.FormatTimestamp(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), "2026-01-01T00:00:00Z"},
+		{"duration", connecterrors.FormatDuration(90 * time.Second), "1m30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"12.50", false},
+		{"-3", false},
+		{"+0.25", false},
+		{"1000.00", false},
+		{"", true},
+		{"-", true},
+		{".5", true},
+		{"5.", true},
+		{"1.2.3", true},
+		{"1e3", true},
+		{"twelve", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := connecterrors.ParseDecimal(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDecimal(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.in {
+				t.Errorf("ParseDecimal(%q) = %q, want the input unchanged", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestParseInvalidParams(t *testing.T) {
+	if _, err := connecterrors.ParseInt("x"); err == nil {
+		t.Error("ParseInt: expected error")
+	}
+	if _, err := connecterrors.ParseBool("x"); err == nil {
+		t.Error("ParseBool: expected error")
+	}
+	if _, err := connecterrors.ParseTimestamp("x"); err == nil {
+		t.Error("ParseTimestamp: expected error")
+	}
+	if _, err := connecterrors.ParseDuration("x"); err == nil {
+		t.Error("ParseDuration: expected error")
+	}
+}
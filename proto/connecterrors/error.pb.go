@@ -0,0 +1,493 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: connecterrors/v1/error.proto
+
+package connecterrorspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Code defines the standard Connect RPC status codes.
+type Code int32
+
+const (
+	Code_CODE_UNSPECIFIED         Code = 0
+	Code_CODE_CANCELED            Code = 1
+	Code_CODE_UNKNOWN             Code = 2
+	Code_CODE_INVALID_ARGUMENT    Code = 3
+	Code_CODE_DEADLINE_EXCEEDED   Code = 4
+	Code_CODE_NOT_FOUND           Code = 5
+	Code_CODE_ALREADY_EXISTS      Code = 6
+	Code_CODE_PERMISSION_DENIED   Code = 7
+	Code_CODE_RESOURCE_EXHAUSTED  Code = 8
+	Code_CODE_FAILED_PRECONDITION Code = 9
+	Code_CODE_ABORTED             Code = 10
+	Code_CODE_OUT_OF_RANGE        Code = 11
+	Code_CODE_UNIMPLEMENTED       Code = 12
+	Code_CODE_INTERNAL            Code = 13
+	Code_CODE_UNAVAILABLE         Code = 14
+	Code_CODE_DATA_LOSS           Code = 15
+	Code_CODE_UNAUTHENTICATED     Code = 16
+)
+
+// Enum value maps for Code.
+var (
+	Code_name = map[int32]string{
+		0:  "CODE_UNSPECIFIED",
+		1:  "CODE_CANCELED",
+		2:  "CODE_UNKNOWN",
+		3:  "CODE_INVALID_ARGUMENT",
+		4:  "CODE_DEADLINE_EXCEEDED",
+		5:  "CODE_NOT_FOUND",
+		6:  "CODE_ALREADY_EXISTS",
+		7:  "CODE_PERMISSION_DENIED",
+		8:  "CODE_RESOURCE_EXHAUSTED",
+		9:  "CODE_FAILED_PRECONDITION",
+		10: "CODE_ABORTED",
+		11: "CODE_OUT_OF_RANGE",
+		12: "CODE_UNIMPLEMENTED",
+		13: "CODE_INTERNAL",
+		14: "CODE_UNAVAILABLE",
+		15: "CODE_DATA_LOSS",
+		16: "CODE_UNAUTHENTICATED",
+	}
+	Code_value = map[string]int32{
+		"CODE_UNSPECIFIED":         0,
+		"CODE_CANCELED":            1,
+		"CODE_UNKNOWN":             2,
+		"CODE_INVALID_ARGUMENT":    3,
+		"CODE_DEADLINE_EXCEEDED":   4,
+		"CODE_NOT_FOUND":           5,
+		"CODE_ALREADY_EXISTS":      6,
+		"CODE_PERMISSION_DENIED":   7,
+		"CODE_RESOURCE_EXHAUSTED":  8,
+		"CODE_FAILED_PRECONDITION": 9,
+		"CODE_ABORTED":             10,
+		"CODE_OUT_OF_RANGE":        11,
+		"CODE_UNIMPLEMENTED":       12,
+		"CODE_INTERNAL":            13,
+		"CODE_UNAVAILABLE":         14,
+		"CODE_DATA_LOSS":           15,
+		"CODE_UNAUTHENTICATED":     16,
+	}
+)
+
+func (x Code) Enum() *Code {
+	p := new(Code)
+	*p = x
+	return p
+}
+
+func (x Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_connecterrors_v1_error_proto_enumTypes[0].Descriptor()
+}
+
+func (Code) Type() protoreflect.EnumType {
+	return &file_connecterrors_v1_error_proto_enumTypes[0]
+}
+
+func (x Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Code.Descriptor instead.
+func (Code) EnumDescriptor() ([]byte, []int) {
+	return file_connecterrors_v1_error_proto_rawDescGZIP(), []int{0}
+}
+
+// Severity classifies how serious an error is, e.g. for alerting and log levels.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_WARNING     Severity = 1
+	Severity_SEVERITY_ERROR       Severity = 2
+	Severity_SEVERITY_CRITICAL    Severity = 3
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_WARNING",
+		2: "SEVERITY_ERROR",
+		3: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_WARNING":     1,
+		"SEVERITY_ERROR":       2,
+		"SEVERITY_CRITICAL":    3,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_connecterrors_v1_error_proto_enumTypes[1].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_connecterrors_v1_error_proto_enumTypes[1]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_connecterrors_v1_error_proto_rawDescGZIP(), []int{1}
+}
+
+// ErrorDef defines a single error that can be attached to an RPC method.
+// It carries all metadata needed for code generation and runtime error handling.
+type ErrorDef struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique error code identifier, e.g. "ERROR_NOT_FOUND".
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Message template with {{placeholder}} syntax, e.g. "User '{{id}}' not found".
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Connect RPC status code.
+	ConnectCode Code `protobuf:"varint,3,opt,name=connect_code,json=connectCode,proto3,enum=connecterrors.v1.Code" json:"connect_code,omitempty"`
+	// Whether the client should retry the request on this error.
+	Retryable bool `protobuf:"varint,4,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// google.rpc.ErrorInfo domain identifying the producing service,
+	// e.g. "payments.acme.com". Overrides the file-level error_domain.
+	Domain string `protobuf:"bytes,5,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Suggested client backoff for retryable errors, sent in google.rpc.RetryInfo
+	// and mirrored into the Retry-After metadata header.
+	RetryDelay *durationpb.Duration `protobuf:"bytes,6,opt,name=retry_delay,json=retryDelay,proto3" json:"retry_delay,omitempty"`
+	// Human-readable explanation of when the error occurs and how to resolve it.
+	// Emitted as the doc comment of the generated constant.
+	Description string `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	// Link to documentation for the error, sent as a google.rpc.Help detail.
+	HelpUrl string `protobuf:"bytes,8,opt,name=help_url,json=helpUrl,proto3" json:"help_url,omitempty"`
+	// How serious the error is.
+	Severity Severity `protobuf:"varint,9,opt,name=severity,proto3,enum=connecterrors.v1.Severity" json:"severity,omitempty"`
+	// Marks the error as deprecated. Generated code carries a Deprecated: marker.
+	Deprecated bool `protobuf:"varint,10,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	// Code of the error that replaces this deprecated one, e.g. "ERROR_USER_MISSING".
+	ReplacedBy string `protobuf:"bytes,11,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	// Message templates keyed by BCP 47 language tag, e.g. "de" or "pt-BR".
+	// Sent as a google.rpc.LocalizedMessage detail for callers asking for that locale.
+	LocalizedMessages map[string]string `protobuf:"bytes,12,rep,name=localized_messages,json=localizedMessages,proto3" json:"localized_messages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Placeholders holding sensitive data, e.g. "email" or "token". Their values are
+	// masked in the client-visible message and omitted from ErrorInfo.metadata.
+	SensitiveFields []string `protobuf:"bytes,13,rep,name=sensitive_fields,json=sensitiveFields,proto3" json:"sensitive_fields,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ErrorDef) Reset() {
+	*x = ErrorDef{}
+	mi := &file_connecterrors_v1_error_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDef) ProtoMessage() {}
+
+func (x *ErrorDef) ProtoReflect() protoreflect.Message {
+	mi := &file_connecterrors_v1_error_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDef.ProtoReflect.Descriptor instead.
+func (*ErrorDef) Descriptor() ([]byte, []int) {
+	return file_connecterrors_v1_error_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrorDef) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetConnectCode() Code {
+	if x != nil {
+		return x.ConnectCode
+	}
+	return Code_CODE_UNSPECIFIED
+}
+
+func (x *ErrorDef) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *ErrorDef) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetRetryDelay() *durationpb.Duration {
+	if x != nil {
+		return x.RetryDelay
+	}
+	return nil
+}
+
+func (x *ErrorDef) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetHelpUrl() string {
+	if x != nil {
+		return x.HelpUrl
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *ErrorDef) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *ErrorDef) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+func (x *ErrorDef) GetLocalizedMessages() map[string]string {
+	if x != nil {
+		return x.LocalizedMessages
+	}
+	return nil
+}
+
+func (x *ErrorDef) GetSensitiveFields() []string {
+	if x != nil {
+		return x.SensitiveFields
+	}
+	return nil
+}
+
+var file_connecterrors_v1_error_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: ([]*ErrorDef)(nil),
+		Field:         50001,
+		Name:          "connecterrors.v1.connect_error",
+		Tag:           "bytes,50001,rep,name=connect_error",
+		Filename:      "connecterrors/v1/error.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: ([]*ErrorDef)(nil),
+		Field:         50002,
+		Name:          "connecterrors.v1.error",
+		Tag:           "bytes,50002,rep,name=error",
+		Filename:      "connecterrors/v1/error.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50003,
+		Name:          "connecterrors.v1.error_domain",
+		Tag:           "bytes,50003,opt,name=error_domain",
+		Filename:      "connecterrors/v1/error.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// repeated connecterrors.v1.ErrorDef connect_error = 50001;
+	E_ConnectError = &file_connecterrors_v1_error_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// repeated connecterrors.v1.ErrorDef error = 50002;
+	E_Error = &file_connecterrors_v1_error_proto_extTypes[1]
+	// Default google.rpc.ErrorInfo domain for all errors defined in the file.
+	//
+	// optional string error_domain = 50003;
+	E_ErrorDomain = &file_connecterrors_v1_error_proto_extTypes[2]
+)
+
+var File_connecterrors_v1_error_proto protoreflect.FileDescriptor
+
+const file_connecterrors_v1_error_proto_rawDesc = "" +
+	"\n" +
+	"\x1cconnecterrors/v1/error.proto\x12\x10connecterrors.v1\x1a google/protobuf/descriptor.proto\x1a\x1egoogle/protobuf/duration.proto\"\xee\x04\n" +
+	"\bErrorDef\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x129\n" +
+	"\fconnect_code\x18\x03 \x01(\x0e2\x16.connecterrors.v1.CodeR\vconnectCode\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\x12\x16\n" +
+	"\x06domain\x18\x05 \x01(\tR\x06domain\x12:\n" +
+	"\vretry_delay\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryDelay\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x19\n" +
+	"\bhelp_url\x18\b \x01(\tR\ahelpUrl\x126\n" +
+	"\bseverity\x18\t \x01(\x0e2\x1a.connecterrors.v1.SeverityR\bseverity\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\n" +
+	" \x01(\bR\n" +
+	"deprecated\x12\x1f\n" +
+	"\vreplaced_by\x18\v \x01(\tR\n" +
+	"replacedBy\x12`\n" +
+	"\x12localized_messages\x18\f \x03(\v21.connecterrors.v1.ErrorDef.LocalizedMessagesEntryR\x11localizedMessages\x12)\n" +
+	"\x10sensitive_fields\x18\r \x03(\tR\x0fsensitiveFields\x1aD\n" +
+	"\x16LocalizedMessagesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\x94\x03\n" +
+	"\x04Code\x12\x14\n" +
+	"\x10CODE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rCODE_CANCELED\x10\x01\x12\x10\n" +
+	"\fCODE_UNKNOWN\x10\x02\x12\x19\n" +
+	"\x15CODE_INVALID_ARGUMENT\x10\x03\x12\x1a\n" +
+	"\x16CODE_DEADLINE_EXCEEDED\x10\x04\x12\x12\n" +
+	"\x0eCODE_NOT_FOUND\x10\x05\x12\x17\n" +
+	"\x13CODE_ALREADY_EXISTS\x10\x06\x12\x1a\n" +
+	"\x16CODE_PERMISSION_DENIED\x10\a\x12\x1b\n" +
+	"\x17CODE_RESOURCE_EXHAUSTED\x10\b\x12\x1c\n" +
+	"\x18CODE_FAILED_PRECONDITION\x10\t\x12\x10\n" +
+	"\fCODE_ABORTED\x10\n" +
+	"\x12\x15\n" +
+	"\x11CODE_OUT_OF_RANGE\x10\v\x12\x16\n" +
+	"\x12CODE_UNIMPLEMENTED\x10\f\x12\x11\n" +
+	"\rCODE_INTERNAL\x10\r\x12\x14\n" +
+	"\x10CODE_UNAVAILABLE\x10\x0e\x12\x12\n" +
+	"\x0eCODE_DATA_LOSS\x10\x0f\x12\x18\n" +
+	"\x14CODE_UNAUTHENTICATED\x10\x10*e\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x01\x12\x12\n" +
+	"\x0eSEVERITY_ERROR\x10\x02\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x03:a\n" +
+	"\rconnect_error\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x03(\v2\x1a.connecterrors.v1.ErrorDefR\fconnectError:P\n" +
+	"\x05error\x12\x1c.google.protobuf.FileOptions\x18҆\x03 \x03(\v2\x1a.connecterrors.v1.ErrorDefR\x05error:A\n" +
+	"\ferror_domain\x12\x1c.google.protobuf.FileOptions\x18ӆ\x03 \x01(\tR\verrorDomainBLZJgithub.com/balcieren/connect-errors-go/proto/connecterrors;connecterrorspbb\x06proto3"
+
+var (
+	file_connecterrors_v1_error_proto_rawDescOnce sync.Once
+	file_connecterrors_v1_error_proto_rawDescData []byte
+)
+
+func file_connecterrors_v1_error_proto_rawDescGZIP() []byte {
+	file_connecterrors_v1_error_proto_rawDescOnce.Do(func() {
+		file_connecterrors_v1_error_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_connecterrors_v1_error_proto_rawDesc), len(file_connecterrors_v1_error_proto_rawDesc)))
+	})
+	return file_connecterrors_v1_error_proto_rawDescData
+}
+
+var file_connecterrors_v1_error_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_connecterrors_v1_error_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_connecterrors_v1_error_proto_goTypes = []any{
+	(Code)(0),                          // 0: connecterrors.v1.Code
+	(Severity)(0),                      // 1: connecterrors.v1.Severity
+	(*ErrorDef)(nil),                   // 2: connecterrors.v1.ErrorDef
+	nil,                                // 3: connecterrors.v1.ErrorDef.LocalizedMessagesEntry
+	(*durationpb.Duration)(nil),        // 4: google.protobuf.Duration
+	(*descriptorpb.MethodOptions)(nil), // 5: google.protobuf.MethodOptions
+	(*descriptorpb.FileOptions)(nil),   // 6: google.protobuf.FileOptions
+}
+var file_connecterrors_v1_error_proto_depIdxs = []int32{
+	0, // 0: connecterrors.v1.ErrorDef.connect_code:type_name -> connecterrors.v1.Code
+	4, // 1: connecterrors.v1.ErrorDef.retry_delay:type_name -> google.protobuf.Duration
+	1, // 2: connecterrors.v1.ErrorDef.severity:type_name -> connecterrors.v1.Severity
+	3, // 3: connecterrors.v1.ErrorDef.localized_messages:type_name -> connecterrors.v1.ErrorDef.LocalizedMessagesEntry
+	5, // 4: connecterrors.v1.connect_error:extendee -> google.protobuf.MethodOptions
+	6, // 5: connecterrors.v1.error:extendee -> google.protobuf.FileOptions
+	6, // 6: connecterrors.v1.error_domain:extendee -> google.protobuf.FileOptions
+	2, // 7: connecterrors.v1.connect_error:type_name -> connecterrors.v1.ErrorDef
+	2, // 8: connecterrors.v1.error:type_name -> connecterrors.v1.ErrorDef
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	7, // [7:9] is the sub-list for extension type_name
+	4, // [4:7] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_connecterrors_v1_error_proto_init() }
+func file_connecterrors_v1_error_proto_init() {
+	if File_connecterrors_v1_error_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_connecterrors_v1_error_proto_rawDesc), len(file_connecterrors_v1_error_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   2,
+			NumExtensions: 3,
+			NumServices:   0,
+		},
+		GoTypes:           file_connecterrors_v1_error_proto_goTypes,
+		DependencyIndexes: file_connecterrors_v1_error_proto_depIdxs,
+		EnumInfos:         file_connecterrors_v1_error_proto_enumTypes,
+		MessageInfos:      file_connecterrors_v1_error_proto_msgTypes,
+		ExtensionInfos:    file_connecterrors_v1_error_proto_extTypes,
+	}.Build()
+	File_connecterrors_v1_error_proto = out.File
+	file_connecterrors_v1_error_proto_goTypes = nil
+	file_connecterrors_v1_error_proto_depIdxs = nil
+}
@@ -0,0 +1,120 @@
+package connecterrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+)
+
+// RecoverFunc is a callback invoked when a handler panics. It receives the
+// context, the RPC spec, the recovered value and the stack of the panicking
+// goroutine, e.g. for logging. The stack is never sent to the client.
+type RecoverFunc func(ctx context.Context, spec connect.Spec, panicValue any, stack []byte)
+
+// PanicError is the cause of the errors returned by RecoverInterceptor. It is
+// reachable on the server with errors.As but is never sent on the wire.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack of the panicking goroutine.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error, so that errors.Is and errors.As see
+// errors passed to panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RecoverInterceptor is a server-side Connect interceptor that recovers panics
+// in unary and streaming handlers and turns them into ErrInternal domain
+// errors. fn, if not nil, is called with the recovered value and stack first.
+// The resulting error carries a *PanicError as its cause and otherwise looks
+// like any other ErrInternal, so the client never sees the panic value or stack.
+//
+// Interceptors listed before RecoverInterceptor in connect.WithInterceptors
+// wrap it, so an ErrorInterceptor placed first observes recovered panics like
+// any other domain error. Panics with http.ErrAbortHandler are re-raised, as
+// net/http expects.
+//
+// Example:
+//
+//	mux.Handle(userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(
+//	        cerr.ErrorInterceptor(logError),
+//	        cerr.RecoverInterceptor(func(ctx context.Context, spec connect.Spec, v any, stack []byte) {
+//	            slog.ErrorContext(ctx, "panic", "procedure", spec.Procedure, "value", v, "stack", string(stack))
+//	        }),
+//	    ),
+//	))
+func RecoverInterceptor(fn RecoverFunc) connect.Interceptor {
+	return defaultRegistry.RecoverInterceptor(fn)
+}
+
+// RecoverInterceptor returns a Connect interceptor that recovers handler panics
+// as ErrInternal errors registered in r. See the package-level RecoverInterceptor.
+func (r *Registry) RecoverInterceptor(fn RecoverFunc) connect.Interceptor {
+	return &recoverInterceptor{reg: r, fn: fn}
+}
+
+// recoverInterceptor implements connect.Interceptor for RecoverInterceptor.
+type recoverInterceptor struct {
+	reg *Registry
+	fn  RecoverFunc
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *recoverInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		defer func() {
+			if v := recover(); v != nil {
+				resp, err = nil, i.recovered(ctx, req.Spec(), v)
+			}
+		}()
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *recoverInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *recoverInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = i.recovered(ctx, conn.Spec(), v)
+			}
+		}()
+		return next(ctx, conn)
+	}
+}
+
+// recovered reports the panic value v to the callback and returns the
+// ErrInternal error sent to the client.
+func (i *recoverInterceptor) recovered(ctx context.Context, spec connect.Spec, v any) error {
+	if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+		panic(v)
+	}
+	stack := debug.Stack()
+	if i.fn != nil {
+		i.fn(ctx, spec, v, stack)
+	}
+	return i.reg.New(ErrInternal, nil, WithCause(&PanicError{Value: v, Stack: stack}))
+}
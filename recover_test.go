@@ -0,0 +1,99 @@
+package connecterrors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+func TestRecoverInterceptor(t *testing.T) {
+	var (
+		panicValue any
+		stack      []byte
+		reported   []connecterrors.ErrorCode
+		serverErr  error
+	)
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Get", connect.NewUnaryHandler(
+		"/test.v1.TestService/Get",
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			panic("secret database password leaked")
+		},
+		connect.WithInterceptors(
+			connecterrors.ErrorInterceptor(func(_ context.Context, err *connect.Error, def connecterrors.Error) {
+				reported = append(reported, def.Code)
+				serverErr = err
+			}),
+			connecterrors.RecoverInterceptor(func(_ context.Context, spec connect.Spec, v any, s []byte) {
+				if spec.Procedure != "/test.v1.TestService/Get" {
+					t.Errorf("Procedure = %q", spec.Procedure)
+				}
+				panicValue, stack = v, s
+			}),
+		),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+"/test.v1.TestService/Get")
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if !connecterrors.MatchError(err, "", connecterrors.ErrInternal) {
+		t.Fatalf("expected ERROR_INTERNAL, got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret") || strings.Contains(err.Error(), "goroutine") {
+		t.Errorf("panic value or stack leaked to the client: %v", err)
+	}
+	if panicValue != "secret database password leaked" || !strings.Contains(string(stack), "goroutine") {
+		t.Errorf("callback got value %v and stack %q", panicValue, stack)
+	}
+	if len(reported) != 1 || reported[0] != connecterrors.ErrInternal {
+		t.Errorf("ErrorInterceptor reported %v, want [ERROR_INTERNAL]", reported)
+	}
+	var panicErr *connecterrors.PanicError
+	if !errors.As(serverErr, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Errorf("expected *PanicError cause on the server, got %v", serverErr)
+	}
+}
+
+func TestRecoverInterceptorStreaming(t *testing.T) {
+	cause := errors.New("nil map write")
+	handler := connecterrors.RecoverInterceptor(nil).WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		panic(cause)
+	})
+	err := handler(context.Background(), &fakeHandlerConn{})
+
+	if !errors.Is(err, connecterrors.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected the panicked error to be reachable with errors.Is")
+	}
+
+	ok := connecterrors.RecoverInterceptor(nil).WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		return nil
+	})
+	if err := ok(context.Background(), &fakeHandlerConn{}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestRecoverInterceptorAbortHandler(t *testing.T) {
+	handler := connecterrors.RecoverInterceptor(nil).WrapStreamingHandler(func(context.Context, connect.StreamingHandlerConn) error {
+		panic(http.ErrAbortHandler)
+	})
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler re-raised", v)
+		}
+	}()
+	_ = handler(context.Background(), &fakeHandlerConn{})
+}
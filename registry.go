@@ -5,6 +5,7 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"connectrpc.com/connect"
 )
@@ -59,6 +60,32 @@ const (
 	ErrDataLoss ErrorCode = "ERROR_DATA_LOSS"
 )
 
+// Severity classifies how serious an error is, e.g. for alerting and log levels.
+// It mirrors the connecterrors.v1.Severity proto enum.
+type Severity int
+
+// Severity levels. SeverityUnspecified is the zero value.
+const (
+	SeverityUnspecified Severity = iota
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// String returns the lower-case name of s, e.g. "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unspecified"
+	}
+}
+
 // Error represents a Connect RPC error definition with template support.
 // It maps a semantic error code to a Connect status code and message template.
 type Error struct {
@@ -73,16 +100,97 @@ type Error struct {
 
 	// Retryable indicates whether the client should retry the request.
 	Retryable bool
+
+	// RetryDelay is the suggested client backoff for retryable errors. It is sent
+	// in the google.rpc.RetryInfo detail and the Retry-After metadata header.
+	RetryDelay time.Duration
+
+	// Domain is the google.rpc.ErrorInfo domain identifying the producing service
+	// (e.g. "payments.acme.com"). If empty, the Registry domain is used.
+	Domain string
+
+	// Description explains when the error occurs and how to resolve it.
+	// It is used as the description of the HelpURL link.
+	Description string
+
+	// HelpURL links to documentation for the error. If set, it is sent in a
+	// google.rpc.Help detail.
+	HelpURL string
+
+	// Severity classifies how serious the error is.
+	Severity Severity
+
+	// Deprecated marks the error as deprecated; ReplacedBy names its replacement, if any.
+	Deprecated bool
+	ReplacedBy ErrorCode
+
+	// SensitiveFields lists template placeholders holding sensitive data, such
+	// as "email" or "token". Their values are masked in the client-visible
+	// message and omitted from the ErrorInfo metadata, but stay available on the
+	// server through CodedError.Data.
+	SensitiveFields []string
 }
 
-// writeMu protects Registry writes. Reads are lock-free via atomic.Value.
-var writeMu sync.Mutex
+// Registry is an error catalog mapping error codes to their definitions.
+// Reads are lock-free via atomic.Value; writes copy-on-write under a mutex.
+//
+// The package-level functions (Register, Lookup, New, ...) operate on a default
+// Registry. Create separate instances with NewRegistry when tests or multiple
+// services in one binary need independent catalogs. The zero value is an empty
+// Registry without the built-in definitions that uses DefaultDomain.
+type Registry struct {
+	// writeMu protects writes. Reads are lock-free via atomic.Value.
+	writeMu sync.Mutex
+
+	// errs stores an immutable map[ErrorCode]Error snapshot.
+	errs atomic.Value
+
+	// domain stores the default ErrorInfo domain string.
+	domain atomic.Value
+
+	// contracts stores an immutable map[string]map[ErrorCode]struct{} snapshot
+	// of per-procedure declared error codes.
+	contracts atomic.Value
 
-// registryVal stores an immutable map[ErrorCode]Error snapshot.
-// Reads are lock-free (atomic load). Writes copy-on-write under writeMu.
-var registryVal atomic.Value
+	// messages stores an immutable map[string]map[ErrorCode]string snapshot
+	// of localized message templates keyed by canonical locale.
+	messages atomic.Value
 
-// defaultErrors is the default error definitions. This is used to initialize registryVal.
+	// mappers stores an immutable []ErrorMapper snapshot used by From.
+	mappers atomic.Value
+
+	// conflictMode, onConflict and conflicts are guarded by writeMu.
+	conflictMode ConflictMode
+	onConflict   ConflictFunc
+	conflicts    []Conflict
+}
+
+// DefaultDomain is the ErrorInfo domain used when neither the Error nor the
+// Registry configures one.
+const DefaultDomain = "connecterrors"
+
+// NewRegistry creates a Registry pre-populated with the built-in error definitions
+// (ErrNotFound, ErrInternal, ...).
+//
+// Example:
+//
+//	reg := cerr.NewRegistry()
+//	reg.Register(cerr.Error{Code: "ERROR_CUSTOM", MessageTpl: "Custom", ConnectCode: connect.CodeInternal})
+//	return nil, reg.New(cerr.ErrorCode("ERROR_CUSTOM"), nil)
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.errs.Store(defaultErrors)
+	r.domain.Store(DefaultDomain)
+	return r
+}
+
+// defaultRegistry backs the package-level functions.
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the Registry used by the package-level functions.
+func DefaultRegistry() *Registry { return defaultRegistry }
+
+// defaultErrors is the default error definitions. This is used to initialize every Registry.
 // After init, use Lookup/Register/RegisterAll to interact with the registry.
 var defaultErrors = map[ErrorCode]Error{
 	ErrNotFound: {
@@ -177,61 +285,32 @@ var defaultErrors = map[ErrorCode]Error{
 	},
 }
 
-// Register adds or updates an error definition in the global Registry.
+// Register adds or updates an error definition in the Registry.
 // It is safe for concurrent use. Uses copy-on-write for lock-free reads.
-//
-// Example:
-//
-//	connecterrors.Register(connecterrors.Error{
-//	    Code:        "ERROR_CUSTOM",
-//	    MessageTpl:  "Custom error: {{detail}}",
-//	    ConnectCode: connect.CodeInternal,
-//	    Retryable:   false,
-//	})
-func Register(err Error) {
-	writeMu.Lock()
-	defer writeMu.Unlock()
-	current := loadRegistry()
-	updated := make(map[ErrorCode]Error, len(current)+1)
-	for k, v := range current {
-		updated[k] = v
-	}
-	updated[err.Code] = err
-	registryVal.Store(updated)
+// A definition that differs from an already registered one is handled
+// according to the ConflictMode (see SetConflictMode).
+func (r *Registry) Register(err Error) {
+	r.register([]Error{err})
 }
 
-// RegisterAll adds multiple error definitions to the global Registry.
+// RegisterAll adds multiple error definitions to the Registry.
 // It is safe for concurrent use. Uses copy-on-write for lock-free reads.
-func RegisterAll(errs []Error) {
-	writeMu.Lock()
-	defer writeMu.Unlock()
-	current := loadRegistry()
-	updated := make(map[ErrorCode]Error, len(current)+len(errs))
-	for k, v := range current {
-		updated[k] = v
-	}
-	for _, err := range errs {
-		updated[err.Code] = err
-	}
-	registryVal.Store(updated)
+// Conflicting definitions are handled as in Register.
+func (r *Registry) RegisterAll(errs []Error) {
+	r.register(errs)
 }
 
 // Lookup retrieves an error definition from the Registry by its code.
 // Lock-free: uses atomic load, no mutex overhead.
-//
-// Example:
-//
-//	e, ok := connecterrors.Lookup(connecterrors.ErrNotFound)
-func Lookup(code ErrorCode) (Error, bool) {
-	m := loadRegistry()
-	e, ok := m[code]
+func (r *Registry) Lookup(code ErrorCode) (Error, bool) {
+	e, ok := r.load()[code]
 	return e, ok
 }
 
 // MustLookup retrieves an error definition by code and panics if not found.
 // Use this only during initialization or in tests.
-func MustLookup(code ErrorCode) Error {
-	e, ok := Lookup(code)
+func (r *Registry) MustLookup(code ErrorCode) Error {
+	e, ok := r.Lookup(code)
 	if !ok {
 		panic(fmt.Sprintf("connecterrors: unknown error code %q", code))
 	}
@@ -239,9 +318,8 @@ func MustLookup(code ErrorCode) Error {
 }
 
 // Codes returns all registered error codes in sorted order.
-// Useful for debugging, documentation, or building admin UIs.
-func Codes() []string {
-	m := loadRegistry()
+func (r *Registry) Codes() []string {
+	m := r.load()
 	codes := make([]string, 0, len(m))
 	for k := range m {
 		codes = append(codes, string(k))
@@ -250,15 +328,95 @@ func Codes() []string {
 	return codes
 }
 
-// loadRegistry returns the current immutable registry snapshot.
-func loadRegistry() map[ErrorCode]Error {
-	v := registryVal.Load()
+// SetDomain sets the default google.rpc.ErrorInfo domain for errors in r that
+// do not define their own Domain. An empty domain restores DefaultDomain.
+// This is safe for concurrent use.
+//
+// Example:
+//
+//	reg.SetDomain("payments.acme.com")
+func (r *Registry) SetDomain(domain string) {
+	if domain == "" {
+		domain = DefaultDomain
+	}
+	r.domain.Store(domain)
+}
+
+// Domain returns the default ErrorInfo domain of r.
+func (r *Registry) Domain() string {
+	v := r.domain.Load()
+	if v == nil {
+		return DefaultDomain
+	}
+	return v.(string)
+}
+
+// domainFor returns the ErrorInfo domain for e, falling back to the Registry domain.
+func (r *Registry) domainFor(e Error) string {
+	if e.Domain != "" {
+		return e.Domain
+	}
+	return r.Domain()
+}
+
+// Errors returns all error definitions in r sorted by code.
+// Useful for exporting the catalog (see the catalog package).
+func (r *Registry) Errors() []Error {
+	m := r.load()
+	errs := make([]Error, 0, len(m))
+	for _, e := range m {
+		errs = append(errs, e)
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Code < errs[j].Code })
+	return errs
+}
+
+// load returns the current immutable registry snapshot.
+func (r *Registry) load() map[ErrorCode]Error {
+	v := r.errs.Load()
 	if v == nil {
 		return nil
 	}
 	return v.(map[ErrorCode]Error)
 }
 
-func init() {
-	registryVal.Store(defaultErrors)
-}
+// Register adds or updates an error definition in the default Registry.
+// It is safe for concurrent use. Uses copy-on-write for lock-free reads.
+//
+// Example:
+//
+//	connecterrors.Register(connecterrors.Error{
+//	    Code:        "ERROR_CUSTOM",
+//	    MessageTpl:  "Custom error: {{detail}}",
+//	    ConnectCode: connect.CodeInternal,
+//	    Retryable:   false,
+//	})
+func Register(err Error) { defaultRegistry.Register(err) }
+
+// RegisterAll adds multiple error definitions to the default Registry.
+// It is safe for concurrent use. Uses copy-on-write for lock-free reads.
+func RegisterAll(errs []Error) { defaultRegistry.RegisterAll(errs) }
+
+// Lookup retrieves an error definition from the default Registry by its code.
+// Lock-free: uses atomic load, no mutex overhead.
+//
+// Example:
+//
+//	e, ok := connecterrors.Lookup(connecterrors.ErrNotFound)
+func Lookup(code ErrorCode) (Error, bool) { return defaultRegistry.Lookup(code) }
+
+// MustLookup retrieves an error definition by code and panics if not found.
+// Use this only during initialization or in tests.
+func MustLookup(code ErrorCode) Error { return defaultRegistry.MustLookup(code) }
+
+// Codes returns all error codes in the default Registry in sorted order.
+// Useful for debugging, documentation, or building admin UIs.
+func Codes() []string { return defaultRegistry.Codes() }
+
+// SetDomain sets the default ErrorInfo domain of the default Registry.
+// This is safe for concurrent use.
+//
+// Example:
+//
+//	cerr.SetDomain("payments.acme.com")
+func SetDomain(domain string) { defaultRegistry.SetDomain(domain) }
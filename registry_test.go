@@ -203,3 +203,105 @@ func TestConcurrentRegistration(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestNewRegistryIsolation(t *testing.T) {
+	a := connecterrors.NewRegistry()
+	b := connecterrors.NewRegistry()
+
+	a.Register(connecterrors.Error{Code: "ERROR_ONLY_A", MessageTpl: "A", ConnectCode: connect.CodeNotFound})
+
+	if _, ok := a.Lookup("ERROR_ONLY_A"); !ok {
+		t.Fatal("expected code in registry a")
+	}
+	if _, ok := b.Lookup("ERROR_ONLY_A"); ok {
+		t.Error("registry b should not see codes registered in a")
+	}
+	if _, ok := connecterrors.Lookup("ERROR_ONLY_A"); ok {
+		t.Error("default registry should not see codes registered in a")
+	}
+}
+
+func TestNewRegistryDefaults(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	if _, ok := reg.Lookup(connecterrors.ErrNotFound); !ok {
+		t.Error("expected built-in codes in a new registry")
+	}
+	if len(reg.Codes()) != 15 {
+		t.Errorf("len(Codes()) = %d, want 15", len(reg.Codes()))
+	}
+}
+
+func TestRegistryNew(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{Code: "ERROR_SCOPED", MessageTpl: "Scoped {{id}}", ConnectCode: connect.CodeAborted})
+
+	err := reg.New(connecterrors.ErrorCode("ERROR_SCOPED"), connecterrors.M{"id": "1"})
+	if err.Code() != connect.CodeAborted {
+		t.Errorf("Code() = %v, want CodeAborted", err.Code())
+	}
+	if def, ok := reg.FromError(err); !ok || def.Code != "ERROR_SCOPED" {
+		t.Errorf("FromError = %v, %v", def, ok)
+	}
+
+	// The default registry does not know the scoped code.
+	if got := connecterrors.New(connecterrors.ErrorCode("ERROR_SCOPED"), nil); got.Code() != connect.CodeInternal {
+		t.Errorf("default New Code() = %v, want CodeInternal", got.Code())
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	connecterrors.Register(connecterrors.Error{Code: "ERROR_VIA_DEFAULT", MessageTpl: "x", ConnectCode: connect.CodeInternal})
+	if _, ok := connecterrors.DefaultRegistry().Lookup("ERROR_VIA_DEFAULT"); !ok {
+		t.Error("package-level Register should write to DefaultRegistry")
+	}
+}
+
+func TestRegistryDomain(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	if reg.Domain() != connecterrors.DefaultDomain {
+		t.Errorf("Domain() = %q, want %q", reg.Domain(), connecterrors.DefaultDomain)
+	}
+
+	reg.SetDomain("users.acme.com")
+	reg.Register(connecterrors.Error{Code: "ERROR_PAY", MessageTpl: "x", ConnectCode: connect.CodeAborted, Domain: "payments.acme.com"})
+
+	info, ok := connecterrors.ExtractErrorInfo(reg.New(connecterrors.ErrNotFound, nil))
+	if !ok || info.Domain != "users.acme.com" {
+		t.Errorf("registry domain: got %v, want users.acme.com", info.GetDomain())
+	}
+
+	info, ok = connecterrors.ExtractErrorInfo(reg.New(connecterrors.ErrorCode("ERROR_PAY"), nil))
+	if !ok || info.Domain != "payments.acme.com" {
+		t.Errorf("error domain: got %v, want payments.acme.com", info.GetDomain())
+	}
+
+	reg.SetDomain("")
+	if reg.Domain() != connecterrors.DefaultDomain {
+		t.Errorf("SetDomain(\"\") should restore DefaultDomain, got %q", reg.Domain())
+	}
+}
+
+func TestZeroRegistry(t *testing.T) {
+	var reg connecterrors.Registry
+	if reg.Domain() != connecterrors.DefaultDomain {
+		t.Errorf("Domain() = %q, want %q", reg.Domain(), connecterrors.DefaultDomain)
+	}
+	reg.Register(connecterrors.Error{Code: "ERROR_ZERO", MessageTpl: "zero", ConnectCode: connect.CodeAborted})
+	info, ok := connecterrors.ExtractErrorInfo(reg.New(connecterrors.ErrorCode("ERROR_ZERO"), nil))
+	if !ok || info.GetDomain() != connecterrors.DefaultDomain {
+		t.Errorf("ErrorInfo = %v, want domain %q", info, connecterrors.DefaultDomain)
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	r := connecterrors.NewRegistry()
+	errs := r.Errors()
+	if len(errs) != len(r.Codes()) {
+		t.Fatalf("Errors() returned %d entries, Codes() %d", len(errs), len(r.Codes()))
+	}
+	for i := 1; i < len(errs); i++ {
+		if errs[i-1].Code >= errs[i].Code {
+			t.Fatalf("Errors() not sorted: %s before %s", errs[i-1].Code, errs[i].Code)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package connecterrors
+
+import "slices"
+
+// Redacted replaces the values of sensitive template data in client-visible
+// messages and of redacted data in log output.
+const Redacted = "[REDACTED]"
+
+// maskSensitive returns data with the non-empty values of fields replaced by
+// Redacted. data itself is returned if it holds none of fields.
+func maskSensitive(data M, fields []string) M {
+	return withoutSensitive(data, fields, true)
+}
+
+// omitSensitive returns data without the keys in fields.
+// data itself is returned if it holds none of fields.
+func omitSensitive(data M, fields []string) M {
+	return withoutSensitive(data, fields, false)
+}
+
+// withoutSensitive implements maskSensitive and omitSensitive.
+func withoutSensitive(data M, fields []string, mask bool) M {
+	var out M
+	for k, v := range data {
+		if !slices.Contains(fields, k) || (mask && v == "") {
+			continue
+		}
+		if out == nil {
+			out = make(M, len(data))
+			for k2, v2 := range data {
+				out[k2] = v2
+			}
+		}
+		if mask {
+			out[k] = Redacted
+		} else {
+			delete(out, k)
+		}
+	}
+	if out == nil {
+		return data
+	}
+	return out
+}
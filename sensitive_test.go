@@ -0,0 +1,77 @@
+package connecterrors_test
+
+import (
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+const errLoginFailed connecterrors.ErrorCode = "ERROR_LOGIN_FAILED"
+
+func newSensitiveRegistry() *connecterrors.Registry {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{
+		Code:            errLoginFailed,
+		MessageTpl:      "Invalid credentials for user '{{email}}' from {{ip}}",
+		ConnectCode:     connect.CodeUnauthenticated,
+		SensitiveFields: []string{"email"},
+	})
+	reg.RegisterMessages("de", map[connecterrors.ErrorCode]string{
+		errLoginFailed: "Ungültige Anmeldedaten für '{{email}}'",
+	})
+	return reg
+}
+
+// received rebuilds err as a client receives it: without the server-side
+// *CodedError but with its message, metadata and details.
+func received(err *connect.Error) *connect.Error {
+	out := connect.NewError(err.Code(), errors.New(err.Message()))
+	for k, v := range err.Meta() {
+		out.Meta()[k] = v
+	}
+	for _, d := range err.Details() {
+		out.AddDetail(d)
+	}
+	return out
+}
+
+func TestSensitiveFields(t *testing.T) {
+	reg := newSensitiveRegistry()
+	data := connecterrors.M{"email": "a@b.com", "ip": "10.0.0.1"}
+	err := reg.New(errLoginFailed, data, connecterrors.WithLocale("de"))
+
+	if want := "Invalid credentials for user '[REDACTED]' from 10.0.0.1"; err.Message() != want {
+		t.Errorf("Message() = %q, want %q", err.Message(), want)
+	}
+	info, ok := connecterrors.ExtractErrorInfo(err)
+	if !ok {
+		t.Fatal("expected ErrorInfo")
+	}
+	if _, ok := info.Metadata["email"]; ok || info.Metadata["ip"] != "10.0.0.1" {
+		t.Errorf("ErrorInfo metadata = %v", info.Metadata)
+	}
+	localized, ok := connecterrors.ExtractLocalizedMessage(err)
+	if !ok || localized.GetMessage() != "Ungültige Anmeldedaten für '[REDACTED]'" {
+		t.Errorf("LocalizedMessage = %v", localized)
+	}
+	if got := connecterrors.ErrorData(err); got["email"] != "a@b.com" {
+		t.Errorf("server ErrorData = %v, want full data", got)
+	}
+
+	decoded := connecterrors.DecodeError(received(err))
+	if got := connecterrors.ErrorData(decoded); got["email"] != "" || got["ip"] != "10.0.0.1" {
+		t.Errorf("client ErrorData = %v, want email omitted", got)
+	}
+}
+
+func TestSensitiveFieldsEmptyValue(t *testing.T) {
+	reg := newSensitiveRegistry()
+	err := reg.New(errLoginFailed, connecterrors.M{"email": "", "ip": "10.0.0.1"})
+
+	if want := "Invalid credentials for user '' from 10.0.0.1"; err.Message() != want {
+		t.Errorf("Message() = %q, want %q", err.Message(), want)
+	}
+}
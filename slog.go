@@ -0,0 +1,229 @@
+package connecterrors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// LogValue implements slog.LogValuer. The error is logged as a group with its
+// code, connect_code, retryable flag, message, internal message (if set with
+// WithInternalMessage), template data and the messages of its cause chain.
+//
+// Example:
+//
+//	var coded *cerr.CodedError
+//	if errors.As(err, &coded) {
+//	    slog.Error("get user failed", "error", coded)
+//	}
+func (e *CodedError) LogValue() slog.Value {
+	return e.logValue(nil)
+}
+
+// logValue implements LogValue, replacing the values of the data keys in
+// redact with "[REDACTED]". The values are masked in the message, internal
+// message and cause chain as well, since those are usually rendered from the
+// same data.
+func (e *CodedError) logValue(redact map[string]bool) slog.Value {
+	if e == nil {
+		return slog.StringValue("<nil>")
+	}
+	mask := e.redactor(redact)
+	attrs := []slog.Attr{
+		slog.String("code", e.code),
+		slog.String("connect_code", e.connectCode.String()),
+		slog.Bool("retryable", e.retryable),
+		slog.String("message", mask.Replace(e.msg)),
+	}
+	if e.internalMsg != "" {
+		attrs = append(attrs, slog.String("internal_message", mask.Replace(e.internalMsg)))
+	}
+	if len(e.data) > 0 {
+		keys := make([]string, 0, len(e.data))
+		for k := range e.data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		data := make([]slog.Attr, len(keys))
+		for i, k := range keys {
+			if redact[k] {
+				data[i] = slog.String(k, Redacted)
+				continue
+			}
+			data[i] = slog.Any(k, e.data[k])
+		}
+		attrs = append(attrs, slog.Attr{Key: "data", Value: slog.GroupValue(data...)})
+	}
+	if causes := causeChain(e.cause); len(causes) > 0 {
+		for i, c := range causes {
+			causes[i] = mask.Replace(c)
+		}
+		attrs = append(attrs, slog.Any("cause", causes))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactor returns a strings.Replacer that replaces the non-empty values of
+// the data keys in redact with Redacted. Longer values are replaced first, so
+// a value containing another one is masked as a whole.
+func (e *CodedError) redactor(redact map[string]bool) *strings.Replacer {
+	var values []string
+	for k := range redact {
+		if v := e.data[k]; v != "" {
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	pairs := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		pairs = append(pairs, v, Redacted)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// causeChain returns the messages of err and the errors it wraps, outermost first.
+func causeChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	return chain
+}
+
+// LogValue implements slog.LogValuer. The definition is logged as a group with
+// its code, message template, connect_code, retryable flag and, if set, domain
+// and severity.
+func (e Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.Code)),
+		slog.String("message", e.MessageTpl),
+		slog.String("connect_code", e.ConnectCode.String()),
+		slog.Bool("retryable", e.Retryable),
+	}
+	if e.Domain != "" {
+		attrs = append(attrs, slog.String("domain", e.Domain))
+	}
+	if e.Severity != SeverityUnspecified {
+		attrs = append(attrs, slog.String("severity", e.Severity.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// SlogOptions configures SlogInterceptor. A nil *SlogOptions uses the defaults.
+type SlogOptions struct {
+	// Message is the log message. Default "rpc error".
+	Message string
+
+	// Level returns the level to log an error with. Default SlogLevel.
+	Level func(code connect.Code) slog.Level
+
+	// Redact lists template data keys whose values are logged as "[REDACTED]".
+	Redact []string
+}
+
+// SlogLevel is the default SlogOptions.Level. Errors caused by the client,
+// such as invalid_argument, not_found or permission_denied, are logged at
+// slog.LevelWarn. Errors of the server, such as internal, unavailable,
+// data_loss or deadline_exceeded, are logged at slog.LevelError.
+func SlogLevel(code connect.Code) slog.Level {
+	switch code {
+	case connect.CodeCanceled, connect.CodeInvalidArgument, connect.CodeNotFound,
+		connect.CodeAlreadyExists, connect.CodePermissionDenied, connect.CodeResourceExhausted,
+		connect.CodeFailedPrecondition, connect.CodeAborted, connect.CodeOutOfRange,
+		connect.CodeUnauthenticated:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+// SlogInterceptor is a server-side Connect interceptor that logs every error
+// returned by unary and streaming handlers to logger. Domain errors are logged
+// with their CodedError group (see CodedError.LogValue), other errors with
+// their message and Connect code. The level is chosen by Connect code.
+//
+// Example:
+//
+//	interceptor := cerr.SlogInterceptor(slog.Default(), &cerr.SlogOptions{
+//	    Redact: []string{"email", "token"},
+//	})
+func SlogInterceptor(logger *slog.Logger, opts *SlogOptions) connect.Interceptor {
+	i := &slogInterceptor{logger: logger, message: "rpc error", level: SlogLevel}
+	if logger == nil {
+		i.logger = slog.Default()
+	}
+	if opts != nil {
+		if opts.Message != "" {
+			i.message = opts.Message
+		}
+		if opts.Level != nil {
+			i.level = opts.Level
+		}
+		if len(opts.Redact) > 0 {
+			i.redact = make(map[string]bool, len(opts.Redact))
+			for _, k := range opts.Redact {
+				i.redact[k] = true
+			}
+		}
+	}
+	return i
+}
+
+// slogInterceptor implements connect.Interceptor for SlogInterceptor.
+type slogInterceptor struct {
+	logger  *slog.Logger
+	message string
+	level   func(connect.Code) slog.Level
+	redact  map[string]bool
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *slogInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil && !req.Spec().IsClient {
+			i.log(ctx, req.Spec().Procedure, err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *slogInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *slogInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, conn)
+		if err != nil {
+			i.log(ctx, conn.Spec().Procedure, err)
+		}
+		return err
+	}
+}
+
+// log writes one record for err.
+func (i *slogInterceptor) log(ctx context.Context, procedure string, err error) {
+	code := connect.CodeOf(err)
+	level := i.level(code)
+	if !i.logger.Enabled(ctx, level) {
+		return
+	}
+	value := slog.GroupValue(
+		slog.String("connect_code", code.String()),
+		slog.String("message", err.Error()),
+	)
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		value = coded.logValue(i.redact)
+	}
+	i.logger.LogAttrs(ctx, level, i.message,
+		slog.String("procedure", procedure),
+		slog.Attr{Key: "error", Value: value},
+	)
+}
@@ -0,0 +1,138 @@
+package connecterrors_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// logRecord decodes the single JSON record written to buf.
+func logRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestCodedErrorLogValue(t *testing.T) {
+	err := connecterrors.Wrap(connecterrors.ErrUnavailable, fmt.Errorf("dial: %w", errors.New("connection refused")), connecterrors.M{"service": "db"})
+	var coded *connecterrors.CodedError
+	if !errors.As(err, &coded) {
+		t.Fatal("expected *CodedError")
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error", coded)
+	got := logRecord(t, &buf)["error"].(map[string]any)
+
+	if got["code"] != "ERROR_UNAVAILABLE" || got["connect_code"] != "unavailable" || got["retryable"] != true {
+		t.Errorf("error group = %v", got)
+	}
+	if data := got["data"].(map[string]any); data["service"] != "db" {
+		t.Errorf("data = %v", data)
+	}
+	causes, _ := got["cause"].([]any)
+	if len(causes) != 2 || causes[0] != "dial: connection refused" || causes[1] != "connection refused" {
+		t.Errorf("cause = %v", got["cause"])
+	}
+}
+
+func TestErrorLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("def", "def", connecterrors.MustLookup(connecterrors.ErrNotFound))
+	got := logRecord(t, &buf)["def"].(map[string]any)
+
+	if got["code"] != "ERROR_NOT_FOUND" || got["message"] != "Resource '{{id}}' not found" || got["connect_code"] != "not_found" {
+		t.Errorf("def group = %v", got)
+	}
+	if _, ok := got["domain"]; ok {
+		t.Error("empty domain must be omitted")
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	if connecterrors.SlogLevel(connect.CodeNotFound) != slog.LevelWarn {
+		t.Error("not_found must be logged at warn")
+	}
+	if connecterrors.SlogLevel(connect.CodeInternal) != slog.LevelError {
+		t.Error("internal must be logged at error")
+	}
+}
+
+func TestSlogInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := connecterrors.SlogInterceptor(logger, &connecterrors.SlogOptions{Redact: []string{"email"}}).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, connecterrors.New(connecterrors.ErrAlreadyExists, connecterrors.M{"id": "7", "email": "a@b.com"})
+		},
+	)
+	_, _ = handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	record := logRecord(t, &buf)
+	if record["level"] != "WARN" || record["msg"] != "rpc error" {
+		t.Errorf("record = %v", record)
+	}
+	data := record["error"].(map[string]any)["data"].(map[string]any)
+	if data["email"] != "[REDACTED]" || data["id"] != "7" {
+		t.Errorf("data = %v", data)
+	}
+}
+
+func TestSlogInterceptorStreaming(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := connecterrors.SlogInterceptor(logger, nil).WrapStreamingHandler(
+		func(context.Context, connect.StreamingHandlerConn) error {
+			return errors.New("boom")
+		},
+	)
+	_ = handler(context.Background(), &fakeHandlerConn{})
+
+	record := logRecord(t, &buf)
+	if record["level"] != "ERROR" {
+		t.Errorf("level = %v, want ERROR", record["level"])
+	}
+	if got := record["error"].(map[string]any); got["connect_code"] != "unknown" || got["message"] != "boom" {
+		t.Errorf("error group = %v", got)
+	}
+}
+
+func TestSlogInterceptorRedactsEverywhere(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := connecterrors.SlogInterceptor(logger, &connecterrors.SlogOptions{Redact: []string{"email"}}).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, connecterrors.NewWithMessage(connecterrors.ErrUnauthenticated,
+				"Invalid credentials for user 'a@b.com'",
+				connecterrors.M{"email": "a@b.com"},
+				connecterrors.WithInternalMessage("login failed for a@b.com"),
+				connecterrors.WithCause(fmt.Errorf("lookup a@b.com: %w", errors.New("no such user"))),
+			)
+		},
+	)
+	_, _ = handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if strings.Contains(buf.String(), "a@b.com") {
+		t.Errorf("redacted value leaked into record: %s", buf.String())
+	}
+	got := logRecord(t, &buf)["error"].(map[string]any)
+	if got["message"] != "Invalid credentials for user '[REDACTED]'" {
+		t.Errorf("message = %v", got["message"])
+	}
+	if got["internal_message"] != "login failed for [REDACTED]" {
+		t.Errorf("internal_message = %v", got["internal_message"])
+	}
+}
@@ -8,13 +8,15 @@ import (
 	"sync"
 )
 
-var templateRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
+// templateRegex matches {{name}} and typed {{name:type}} placeholders.
+var templateRegex = regexp.MustCompile(`\{\{(\w+)(?::(\w+))?\}\}`)
 
 // templatePart represents a segment of a parsed template.
 // Each part has a literal prefix and an optional placeholder field name.
 type templatePart struct {
 	literal string // literal text before the placeholder
 	field   string // placeholder field name; empty for trailing literal
+	raw     string // placeholder text as written, e.g. "{{amount:decimal}}"
 }
 
 // partsCache caches parsed template parts keyed by template string.
@@ -34,6 +36,7 @@ func parseTemplateParts(tpl string) []templatePart {
 		parts = append(parts, templatePart{
 			literal: tpl[last:m[0]],
 			field:   tpl[m[2]:m[3]],
+			raw:     tpl[m[0]:m[1]],
 		})
 		last = m[1]
 	}
@@ -54,6 +57,7 @@ func cachedParts(tpl string) []templatePart {
 }
 
 // TemplateFields extracts all unique placeholder field names from a template string.
+// Type suffixes are dropped, so "{{amount:decimal}}" yields "amount".
 // Fields are returned in sorted order for deterministic output.
 func TemplateFields(template string) []string {
 	matches := templateRegex.FindAllStringSubmatch(template, -1)
@@ -114,7 +118,9 @@ func ValidateTemplate(template string, data M) error {
 }
 
 // FormatTemplate replaces all placeholders in the template with corresponding
-// values from the data map. Unmatched placeholders are left unchanged.
+// values from the data map. Typed placeholders such as "{{amount:decimal}}" are
+// looked up by name; values are expected to be pre-formatted with the
+// FormatXxx helpers. Unmatched placeholders are left unchanged.
 //
 // Uses cached pre-parsed template parts to avoid regex execution on repeated calls.
 func FormatTemplate(template string, data M) string {
@@ -132,9 +138,7 @@ func FormatTemplate(template string, data M) string {
 			if val, ok := data[p.field]; ok {
 				b.WriteString(val)
 			} else {
-				b.WriteString("{{")
-				b.WriteString(p.field)
-				b.WriteString("}}")
+				b.WriteString(p.raw)
 			}
 		}
 	}
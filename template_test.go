@@ -107,3 +107,18 @@ func TestMissingFieldErrorMessage(t *testing.T) {
 		t.Errorf("Error() = %q, want %q", got, want)
 	}
 }
+
+func TestTypedPlaceholders(t *testing.T) {
+	tpl := "Requested {{amount:decimal}} of {{limit:int}} ({{missing:int}})"
+	if got := connecterrors.TemplateFields(tpl); len(got) != 3 || got[0] != "amount" {
+		t.Errorf("TemplateFields = %v, want names without type suffix", got)
+	}
+
+	got := connecterrors.FormatTemplate(tpl, connecterrors.M{
+		"amount": "12.50",
+		"limit":  connecterrors.FormatInt(10),
+	})
+	if want := "Requested 12.50 of 10 ({{missing:int}})"; got != want {
+		t.Errorf("FormatTemplate = %q, want %q", got, want)
+	}
+}
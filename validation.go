@@ -0,0 +1,404 @@
+package connecterrors
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldViolation is a single invalid field reported by a validation library.
+type FieldViolation struct {
+	// Field is the path of the invalid field, e.g. "address.zip_code" or "items[2].sku".
+	Field string
+
+	// Description explains why the field is invalid.
+	Description string
+}
+
+// ValidateFunc validates a request message and returns a validation error, or nil.
+//
+// Example (protovalidate):
+//
+//	validator, _ := protovalidate.New()
+//	validate := func(msg any) error { return validator.Validate(msg.(proto.Message)) }
+type ValidateFunc func(msg any) error
+
+// ViolationExtractor returns the field violations carried by err. It reports
+// false if err is not a validation error.
+type ViolationExtractor func(err error) ([]FieldViolation, bool)
+
+// ValidationOption configures FromValidationError and ValidationInterceptor.
+type ValidationOption func(*validationConfig)
+
+// validationConfig holds the settings collected from ValidationOption values.
+type validationConfig struct {
+	code    ErrorCoder
+	data    M
+	extract ViolationExtractor
+}
+
+// WithValidationCode sets the registered error code and template data used for
+// validation errors (default ErrInvalidArgument). A {{reason}} placeholder not
+// set in data is filled with a summary of the violations.
+func WithValidationCode(code ErrorCoder, data M) ValidationOption {
+	return func(c *validationConfig) {
+		c.code = code
+		c.data = data
+	}
+}
+
+// WithViolationExtractor replaces the default ViolationExtractor (see
+// ExtractViolations), e.g. to support another validation library.
+func WithViolationExtractor(fn ViolationExtractor) ValidationOption {
+	return func(c *validationConfig) {
+		c.extract = fn
+	}
+}
+
+// newValidationConfig collects opts into a validationConfig.
+func newValidationConfig(opts []ValidationOption) validationConfig {
+	c := validationConfig{code: ErrInvalidArgument, extract: ExtractViolations}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	return c
+}
+
+// FromValidationError translates a validation error into a registered domain
+// error with a google.rpc.BadRequest detail holding one FieldViolation per
+// violation. The original error is kept as the cause. It returns err unchanged
+// if it is nil, already a *connect.Error, or not a validation error.
+//
+// Example:
+//
+//	if err := validator.Validate(req.Msg); err != nil {
+//	    return nil, cerr.FromValidationError(err)
+//	}
+func FromValidationError(err error, opts ...ValidationOption) error {
+	return defaultRegistry.FromValidationError(err, opts...)
+}
+
+// FromValidationError translates a validation error into a domain error
+// registered in r. See the package-level FromValidationError.
+func (r *Registry) FromValidationError(err error, opts ...ValidationOption) error {
+	return r.fromValidationError(err, newValidationConfig(opts))
+}
+
+// fromValidationError implements FromValidationError for a collected config.
+func (r *Registry) fromValidationError(err error, c validationConfig) error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return err
+	}
+	violations, ok := c.extract(err)
+	if !ok || len(violations) == 0 {
+		return err
+	}
+	b := r.BadRequest().Code(c.code, c.data)
+	for _, v := range violations {
+		b.Field(v.Field, v.Description)
+	}
+	return b.Err(WithCause(err))
+}
+
+// ValidationInterceptor is a server-side Connect interceptor that makes
+// validation failures look like any other domain error on the wire. It
+// validates every request message with validate before the handler runs and
+// translates validation errors returned by handlers, both via FromValidationError.
+//
+// If validate is nil, messages are validated with their own ValidateAll() or
+// Validate() method, as generated by protoc-gen-validate.
+//
+// Example:
+//
+//	validator, _ := protovalidate.New()
+//	interceptor := cerr.ValidationInterceptor(
+//	    func(msg any) error { return validator.Validate(msg.(proto.Message)) },
+//	    cerr.WithValidationCode(userv1.ErrInvalidRequest, nil),
+//	)
+func ValidationInterceptor(validate ValidateFunc, opts ...ValidationOption) connect.Interceptor {
+	return defaultRegistry.ValidationInterceptor(validate, opts...)
+}
+
+// ValidationInterceptor returns a Connect interceptor that translates
+// validation errors into domain errors registered in r. See the package-level
+// ValidationInterceptor.
+func (r *Registry) ValidationInterceptor(validate ValidateFunc, opts ...ValidationOption) connect.Interceptor {
+	if validate == nil {
+		validate = validateMethod
+	}
+	return &validationInterceptor{reg: r, validate: validate, config: newValidationConfig(opts)}
+}
+
+// validationInterceptor implements connect.Interceptor for ValidationInterceptor.
+type validationInterceptor struct {
+	reg      *Registry
+	validate ValidateFunc
+	config   validationConfig
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *validationInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.validate(req.Any()); err != nil {
+			return nil, i.translate(err)
+		}
+		resp, err := next(ctx, req)
+		if err != nil {
+			err = i.translate(err)
+		}
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor. Clients are passed through unchanged.
+func (i *validationInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor. Each received message
+// is validated before Receive returns it.
+func (i *validationInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, &validatingHandlerConn{StreamingHandlerConn: conn, interceptor: i})
+		if err != nil {
+			err = i.translate(err)
+		}
+		return err
+	}
+}
+
+// translate converts err with FromValidationError. Errors that are not
+// validation errors are returned unchanged.
+func (i *validationInterceptor) translate(err error) error {
+	return i.reg.fromValidationError(err, i.config)
+}
+
+// validatingHandlerConn wraps a server-side stream to validate received messages.
+type validatingHandlerConn struct {
+	connect.StreamingHandlerConn
+	interceptor *validationInterceptor
+}
+
+// Receive implements connect.StreamingHandlerConn.
+func (c *validatingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	if err := c.interceptor.validate(msg); err != nil {
+		return c.interceptor.translate(err)
+	}
+	return nil
+}
+
+// validateMethod validates msg with its protoc-gen-validate ValidateAll or
+// Validate method. Messages without either are considered valid.
+func validateMethod(msg any) error {
+	switch m := msg.(type) {
+	case interface{ ValidateAll() error }:
+		return m.ValidateAll()
+	case interface{ Validate() error }:
+		return m.Validate()
+	}
+	return nil
+}
+
+// ExtractViolations is the default ViolationExtractor. It understands:
+//
+//   - protovalidate errors, whose ToProto method returns a
+//     buf.validate.Violations message. The method is looked up reflectively,
+//     since protovalidate returns its concrete *validate.Violations type;
+//   - protoc-gen-validate field errors and the multi-errors returned by
+//     their ValidateAll methods, if they hold at least one field error.
+func ExtractViolations(err error) ([]FieldViolation, bool) {
+	var found []FieldViolation
+	walkErrors(err, func(e error) bool {
+		if msg, ok := toProto(e); ok {
+			if violations, ok := ViolationsFromProto(msg); ok {
+				found = violations
+				return true
+			}
+		}
+		if all, ok := e.(interface{ AllErrors() []error }); ok {
+			for _, fe := range all.AllErrors() {
+				if v, ok := pgvViolation(fe); ok {
+					found = append(found, v)
+				}
+			}
+			return len(found) > 0
+		}
+		if v, ok := pgvViolation(e); ok {
+			found = []FieldViolation{v}
+			return true
+		}
+		return false
+	})
+	return found, len(found) > 0
+}
+
+// protoMessageType is the reflect.Type of the proto.Message interface.
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// toProto calls the ToProto method of err if it takes no arguments and returns
+// a single, non-nil proto.Message of any concrete type, as protovalidate's
+// ValidationError does. The method is found reflectively, so protovalidate is
+// not a dependency of this package.
+func toProto(err error) (proto.Message, bool) {
+	method := reflect.ValueOf(err).MethodByName("ToProto")
+	if !method.IsValid() {
+		return nil, false
+	}
+	typ := method.Type()
+	if typ.NumIn() != 0 || typ.NumOut() != 1 || !typ.Out(0).Implements(protoMessageType) {
+		return nil, false
+	}
+	out := method.Call(nil)[0]
+	if (out.Kind() == reflect.Pointer || out.Kind() == reflect.Interface) && out.IsNil() {
+		return nil, false
+	}
+	msg, ok := out.Interface().(proto.Message)
+	return msg, ok
+}
+
+// pgvFieldError is the method set of the field errors generated by
+// protoc-gen-validate.
+type pgvFieldError interface {
+	error
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+}
+
+// walkErrors calls fn for err and every error it wraps, depth first, until fn returns true.
+func walkErrors(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if walkErrors(e, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pgvViolation converts a protoc-gen-validate field error.
+func pgvViolation(err error) (FieldViolation, bool) {
+	fe, ok := err.(pgvFieldError)
+	if !ok {
+		return FieldViolation{}, false
+	}
+	return FieldViolation{Field: fe.Field(), Description: fe.Reason()}, true
+}
+
+// violationsMessages lists the full names of the messages understood by
+// ViolationsFromProto. protoc-gen-validate has no message form: its errors are
+// read through their Field and Reason methods instead.
+var violationsMessages = map[protoreflect.FullName]bool{
+	"buf.validate.Violations": true,
+}
+
+// ViolationsFromProto converts a buf.validate.Violations message, as returned
+// by protovalidate's ValidationError.ToProto, into field violations. The
+// message is read through protoreflect, so protovalidate is not a dependency
+// of this package. It reports false if msg is not a Violations message.
+func ViolationsFromProto(msg proto.Message) ([]FieldViolation, bool) {
+	if msg == nil {
+		return nil, false
+	}
+	m := msg.ProtoReflect()
+	if !m.IsValid() || !violationsMessages[m.Descriptor().FullName()] {
+		return nil, false
+	}
+	list := m.Descriptor().Fields().ByName("violations")
+	if list == nil || !list.IsList() || list.Message() == nil {
+		return nil, false
+	}
+
+	var violations []FieldViolation
+	items := m.Get(list).List()
+	for j := 0; j < items.Len(); j++ {
+		v := items.Get(j).Message()
+		fields := v.Descriptor().Fields()
+		violation := FieldViolation{
+			Description: stringField(v, fields.ByName("message")),
+			Field:       stringField(v, fields.ByName("field_path")),
+		}
+		if fd := fields.ByName("field"); fd != nil && fd.Message() != nil && v.Has(fd) {
+			violation.Field = fieldPathString(v.Get(fd).Message())
+		}
+		violations = append(violations, violation)
+	}
+	return violations, true
+}
+
+// stringField returns the value of the string field fd of m, or "" if fd is
+// nil or not a string.
+func stringField(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return ""
+	}
+	return m.Get(fd).String()
+}
+
+// fieldPathString renders a buf.validate.FieldPath as a dotted path with
+// subscripts, e.g. "items[2].sku" or `labels["env"]`.
+func fieldPathString(path protoreflect.Message) string {
+	fd := path.Descriptor().Fields().ByName("elements")
+	if fd == nil || !fd.IsList() {
+		return ""
+	}
+	var b strings.Builder
+	elements := path.Get(fd).List()
+	for i := 0; i < elements.Len(); i++ {
+		e := elements.Get(i).Message()
+		fields := e.Descriptor().Fields()
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(stringField(e, fields.ByName("field_name")))
+		for _, name := range []protoreflect.Name{"index", "bool_key", "int_key", "uint_key", "string_key"} {
+			sub := fields.ByName(name)
+			if sub == nil || !e.Has(sub) {
+				continue
+			}
+			v := e.Get(sub)
+			switch sub.Kind() {
+			case protoreflect.StringKind:
+				b.WriteString("[" + strconv.Quote(v.String()) + "]")
+			case protoreflect.BoolKind:
+				b.WriteString("[" + strconv.FormatBool(v.Bool()) + "]")
+			case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+				protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+				b.WriteString("[" + strconv.FormatInt(v.Int(), 10) + "]")
+			default:
+				b.WriteString("[" + strconv.FormatUint(v.Uint(), 10) + "]")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,264 @@
+package connecterrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	connecterrors "github.com/balcieren/connect-errors-go"
+)
+
+// pgvFieldError mimics a protoc-gen-validate field error.
+type pgvFieldError struct{ field, reason string }
+
+func (e pgvFieldError) Field() string     { return e.field }
+func (e pgvFieldError) Reason() string    { return e.reason }
+func (e pgvFieldError) Key() bool         { return false }
+func (e pgvFieldError) Cause() error      { return nil }
+func (e pgvFieldError) ErrorName() string { return "CreateUserRequestValidationError" }
+func (e pgvFieldError) Error() string     { return e.field + ": " + e.reason }
+
+// fieldReasonError has Field and Reason methods but is not a
+// protoc-gen-validate error.
+type fieldReasonError struct{}
+
+func (fieldReasonError) Field() string  { return "name" }
+func (fieldReasonError) Reason() string { return "conflict" }
+func (fieldReasonError) Error() string  { return "name: conflict" }
+
+// pgvMultiError mimics a protoc-gen-validate multi-error from ValidateAll.
+type pgvMultiError []error
+
+func (m pgvMultiError) AllErrors() []error { return m }
+func (m pgvMultiError) Error() string      { return fmt.Sprintf("%d errors", len(m)) }
+
+// protoValidationError mimics a protovalidate error, whose ToProto method
+// returns the concrete buf.validate.Violations type rather than proto.Message.
+type protoValidationError struct{ violations *dynamicpb.Message }
+
+func (e *protoValidationError) ToProto() *dynamicpb.Message { return e.violations }
+func (e *protoValidationError) Error() string               { return "validation error" }
+
+// newProtoValidationError builds a buf.validate.Violations-shaped message with
+// one violation for items[2].sku and one for labels["env"].
+func newProtoValidationError(t *testing.T) error {
+	t.Helper()
+	opt := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	rep := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("buf/validate/fake.proto"),
+		Package: proto.String("buf.validate"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("FieldPathElement"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("field_name"), Number: proto.Int32(2), Label: opt, Type: str},
+					{Name: proto.String("index"), Number: proto.Int32(6), Label: opt, Type: descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum(), OneofIndex: proto.Int32(0)},
+					{Name: proto.String("string_key"), Number: proto.Int32(10), Label: opt, Type: str, OneofIndex: proto.Int32(0)},
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("subscript")}},
+			},
+			{
+				Name:  proto.String("FieldPath"),
+				Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("elements"), Number: proto.Int32(1), Label: rep, Type: msg, TypeName: proto.String(".buf.validate.FieldPathElement")}},
+			},
+			{
+				Name: proto.String("Violation"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("message"), Number: proto.Int32(3), Label: opt, Type: str},
+					{Name: proto.String("field"), Number: proto.Int32(5), Label: opt, Type: msg, TypeName: proto.String(".buf.validate.FieldPath")},
+				},
+			},
+			{
+				Name:  proto.String("Violations"),
+				Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("violations"), Number: proto.Int32(1), Label: rep, Type: msg, TypeName: proto.String(".buf.validate.Violation")}},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := fd.Messages()
+
+	element := func(name string, sub protoreflect.Name, v protoreflect.Value) protoreflect.Value {
+		e := dynamicpb.NewMessage(types.ByName("FieldPathElement"))
+		e.Set(e.Descriptor().Fields().ByName("field_name"), protoreflect.ValueOfString(name))
+		if sub != "" {
+			e.Set(e.Descriptor().Fields().ByName(sub), v)
+		}
+		return protoreflect.ValueOfMessage(e)
+	}
+	violation := func(message string, elements ...protoreflect.Value) protoreflect.Value {
+		path := dynamicpb.NewMessage(types.ByName("FieldPath"))
+		list := path.Mutable(path.Descriptor().Fields().ByName("elements")).List()
+		for _, e := range elements {
+			list.Append(e)
+		}
+		v := dynamicpb.NewMessage(types.ByName("Violation"))
+		v.Set(v.Descriptor().Fields().ByName("message"), protoreflect.ValueOfString(message))
+		v.Set(v.Descriptor().Fields().ByName("field"), protoreflect.ValueOfMessage(path))
+		return protoreflect.ValueOfMessage(v)
+	}
+
+	violations := dynamicpb.NewMessage(types.ByName("Violations"))
+	list := violations.Mutable(violations.Descriptor().Fields().ByName("violations")).List()
+	list.Append(violation("value is required",
+		element("items", "index", protoreflect.ValueOfUint64(2)),
+		element("sku", "", protoreflect.Value{}),
+	))
+	list.Append(violation("must be lowercase",
+		element("labels", "string_key", protoreflect.ValueOfString("env")),
+	))
+	return &protoValidationError{violations: violations}
+}
+
+func TestExtractViolationsProtovalidate(t *testing.T) {
+	got, ok := connecterrors.ExtractViolations(fmt.Errorf("validate: %w", newProtoValidationError(t)))
+	if !ok {
+		t.Fatal("expected protovalidate error to be recognized")
+	}
+	want := []connecterrors.FieldViolation{
+		{Field: "items[2].sku", Description: "value is required"},
+		{Field: `labels["env"]`, Description: "must be lowercase"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ExtractViolations = %v, want %v", got, want)
+	}
+}
+
+func TestExtractViolationsPGV(t *testing.T) {
+	got, ok := connecterrors.ExtractViolations(pgvMultiError{
+		pgvFieldError{"email", "must be valid"},
+		pgvFieldError{"age", "must be >= 18"},
+	})
+	if !ok || len(got) != 2 || got[1].Field != "age" || got[1].Description != "must be >= 18" {
+		t.Errorf("ExtractViolations = %v, %t", got, ok)
+	}
+
+	for _, err := range []error{errors.New("boom"), fieldReasonError{}, pgvMultiError{}, pgvMultiError{errors.New("boom")}} {
+		if _, ok := connecterrors.ExtractViolations(err); ok {
+			t.Errorf("%#v must not be recognized as a validation error", err)
+		}
+	}
+}
+
+func TestViolationsFromProto(t *testing.T) {
+	var pe *protoValidationError
+	if !errors.As(newProtoValidationError(t), &pe) {
+		t.Fatal("expected ToProto method")
+	}
+	got, ok := connecterrors.ViolationsFromProto(pe.ToProto())
+	if !ok || len(got) != 2 || got[0].Field != "items[2].sku" {
+		t.Errorf("ViolationsFromProto = %v, %t", got, ok)
+	}
+	if _, ok := connecterrors.ViolationsFromProto(&emptypb.Empty{}); ok {
+		t.Error("Empty must not be recognized as Violations")
+	}
+	quota := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user:1", Description: "limit"}}}
+	if _, ok := connecterrors.ViolationsFromProto(quota); ok {
+		t.Error("QuotaFailure must not be recognized as Violations")
+	}
+	if _, ok := connecterrors.ExtractViolations(&protoValidationError{}); ok {
+		t.Error("a nil ToProto result must not be recognized")
+	}
+}
+
+func TestFromValidationError(t *testing.T) {
+	cause := pgvFieldError{"email", "must be valid"}
+	err := connecterrors.FromValidationError(cause)
+
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("CodeOf = %v, want invalid_argument", connect.CodeOf(err))
+	}
+	if got := connecterrors.FieldViolations(err); got["email"] != "must be valid" {
+		t.Errorf("FieldViolations = %v", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected the validation error to be kept as the cause")
+	}
+
+	plain := errors.New("boom")
+	if got := connecterrors.FromValidationError(plain); got != plain {
+		t.Errorf("FromValidationError(plain) = %v, want it unchanged", got)
+	}
+	if connecterrors.FromValidationError(nil) != nil {
+		t.Error("FromValidationError(nil) != nil")
+	}
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	reg := connecterrors.NewRegistry()
+	reg.Register(connecterrors.Error{
+		Code:        "ERROR_INVALID_REQUEST",
+		MessageTpl:  "Invalid request: {{reason}}",
+		ConnectCode: connect.CodeInvalidArgument,
+	})
+	interceptor := reg.ValidationInterceptor(
+		func(any) error { return pgvFieldError{"name", "must not be empty"} },
+		connecterrors.WithValidationCode(connecterrors.ErrorCode("ERROR_INVALID_REQUEST"), nil),
+	)
+
+	called := false
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Get", connect.NewUnaryHandler(
+		"/test.v1.TestService/Get",
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			called = true
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(interceptor),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+"/test.v1.TestService/Get")
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if called {
+		t.Error("handler must not run for an invalid request")
+	}
+	if !connecterrors.MatchError(err, "", connecterrors.ErrorCode("ERROR_INVALID_REQUEST")) {
+		t.Fatalf("expected ERROR_INVALID_REQUEST, got %v", err)
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected *connect.Error, got %T", err)
+	}
+	if connectErr.Message() != "Invalid request: name: must not be empty" {
+		t.Errorf("Message = %q", connectErr.Message())
+	}
+	if got := connecterrors.FieldViolations(err); got["name"] != "must not be empty" {
+		t.Errorf("FieldViolations = %v", got)
+	}
+}
+
+func TestValidationInterceptorHandlerError(t *testing.T) {
+	handler := connecterrors.ValidationInterceptor(nil).WrapUnary(
+		func(context.Context, connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, pgvMultiError{pgvFieldError{"email", "must be valid"}}
+		},
+	)
+	_, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	if !errors.Is(err, connecterrors.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+	if got := connecterrors.FieldViolations(err); got["email"] != "must be valid" {
+		t.Errorf("FieldViolations = %v", got)
+	}
+}